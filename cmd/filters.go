@@ -0,0 +1,13 @@
+package command
+
+import (
+	"github.com/copito/goscaffold/controller"
+	"github.com/spf13/cobra"
+)
+
+var FiltersCmd = &cobra.Command{
+	Use:   "filters",
+	Short: "Lists the filters available to templates",
+	Long:  `Lists the filters available to templates (e.g. {{ scaffold.project_name | snake_case }})`,
+	Run:   controller.Filters,
+}
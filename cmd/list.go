@@ -0,0 +1,19 @@
+package command
+
+import (
+	"github.com/copito/goscaffold/controller"
+	"github.com/spf13/cobra"
+)
+
+var ListCmd = &cobra.Command{
+	Use:   "list <source>",
+	Short: "Lists the templates available in a template catalog",
+	Long:  `Lists the templates available in a template catalog (scaffold-catalog.yaml)`,
+	Args:  cobra.MaximumNArgs(1),
+	Run:   controller.List,
+}
+
+func init() {
+	// local flags
+	ListCmd.Flags().StringP("format", "f", "table", "output format (table or json)")
+}
@@ -0,0 +1,14 @@
+package command
+
+import (
+	"github.com/copito/goscaffold/controller"
+	"github.com/spf13/cobra"
+)
+
+var ResolveCmd = &cobra.Command{
+	Use:   "resolve <name>",
+	Short: "Shows what a template source abbreviation expands to",
+	Long:  `Shows what a template source abbreviation (e.g. gh:org/repo) expands to`,
+	Args:  cobra.ExactArgs(1),
+	Run:   controller.Resolve,
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/copito/goscaffold/core"
 	"github.com/copito/goscaffold/setup"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -24,7 +25,8 @@ One can use scaffold to generate projects based on YAML configurations`,
 		// Check if color should be used
 		useNoColor, _ := cmd.Flags().GetBool("no-color")
 		useVerbose, _ := cmd.Flags().GetBool("verbose")
-		isDryRun, _ := cmd.Flags().GetBool("dry-run")
+		useDebug, _ := cmd.Flags().GetBool("debug")
+		dryRunFormat, _ := cmd.Flags().GetString("dry-run")
 
 		// Setup Logging
 		lvl := new(slog.LevelVar)
@@ -32,18 +34,19 @@ One can use scaffold to generate projects based on YAML configurations`,
 		if useVerbose {
 			lvl.Set(slog.LevelInfo)
 		}
-		if isDryRun {
+		if useDebug {
 			lvl.Set(slog.LevelDebug)
 		}
 		logger := setup.SetupLogging(!useNoColor, lvl.Level())
 
 		// Setup all configuration for this application
 		setup.SetupConfig(logger)
+		setup.SetupUserConfig(logger)
 
 		// Create a new context with the logger attached
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", logger)
-		ctx = context.WithValue(ctx, "dry_run", isDryRun)
+		ctx = context.WithValue(ctx, "dry_run", dryRunFormat != "")
 		cmd.SetContext(ctx)
 	},
 }
@@ -62,11 +65,14 @@ func init() {
 	// persistent flags
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "activate verbose mode for more details")
 	rootCmd.PersistentFlags().BoolP("no-color", "n", false, "disable colors on logs/debugs")
-	rootCmd.PersistentFlags().BoolP("dry-run", "d", false, "allows to test command without side-effects")
+	rootCmd.PersistentFlags().Bool("debug", false, "activate debug logs (e.g. why a file was not rendered)")
+	rootCmd.PersistentFlags().StringP("dry-run", "d", "", "render without writing anything and print what would be generated (tree or json)")
+	rootCmd.PersistentFlags().Lookup("dry-run").NoOptDefVal = core.DryRunTree
 
 	// connect to viper
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("no-color", rootCmd.PersistentFlags().Lookup("no-color"))
+	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
 	viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
 
 	// local flags;
@@ -76,5 +82,8 @@ func init() {
 
 	rootCmd.AddCommand(VerisonCmd)
 	rootCmd.AddCommand(RunCmd)
+	rootCmd.AddCommand(ListCmd)
+	rootCmd.AddCommand(ResolveCmd)
+	rootCmd.AddCommand(FiltersCmd)
 	// rootCmd.AddCommand(initCmd)
 }
@@ -2,14 +2,16 @@ package command
 
 import (
 	"github.com/copito/goscaffold/controller"
+	"github.com/copito/goscaffold/core"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var RunCmd = &cobra.Command{
-	Use:   "run",
+	Use:   "run [source]",
 	Short: "Runs the builder on a scaffold project",
 	Long:  `Runs the builder on a scaffold project`,
+	Args:  cobra.MaximumNArgs(1),
 	Run:   controller.Run,
 }
 
@@ -19,7 +21,19 @@ func init() {
 
 	// persistent flags
 	RunCmd.PersistentFlags().StringP("config", "c", "./scaffold.yaml", "configuration file")
+	RunCmd.PersistentFlags().String("directory", "", "subdirectory of the template source to use as the template root")
+	RunCmd.PersistentFlags().StringP("output-dir", "o", "", "folder the project is generated in (defaults to the current folder)")
+	RunCmd.PersistentFlags().Bool("overwrite", false, "overwrite files that already exist in the output folder")
+	RunCmd.PersistentFlags().Bool("skip-existing", false, "keep files that already exist in the output folder")
+	RunCmd.PersistentFlags().IntP("workers", "w", core.DefaultWorkers(), "number of files rendered concurrently")
+
+	RunCmd.MarkFlagsMutuallyExclusive("overwrite", "skip-existing")
 
 	// connect to viper
 	viper.BindPFlag("config", RunCmd.PersistentFlags().Lookup("config"))
+	viper.BindPFlag("directory", RunCmd.PersistentFlags().Lookup("directory"))
+	viper.BindPFlag("output-dir", RunCmd.PersistentFlags().Lookup("output-dir"))
+	viper.BindPFlag("overwrite", RunCmd.PersistentFlags().Lookup("overwrite"))
+	viper.BindPFlag("skip-existing", RunCmd.PersistentFlags().Lookup("skip-existing"))
+	viper.BindPFlag("workers", RunCmd.PersistentFlags().Lookup("workers"))
 }
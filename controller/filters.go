@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/spf13/cobra"
+)
+
+func Filters(cmd *cobra.Command, args []string) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "FILTER\tEXAMPLE")
+	for _, filter := range core.Filters {
+		fmt.Fprintf(writer, "%s\t%s\n", filter.Name, filter.Description)
+	}
+	writer.Flush()
+}
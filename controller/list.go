@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/spf13/cobra"
+)
+
+func List(cmd *cobra.Command, args []string) {
+	// Get Logger
+	logger := cmd.Context().Value("logger").(*slog.Logger)
+
+	source := "."
+	if len(args) > 0 {
+		source = args[0]
+	}
+
+	sourcePath, _, cleanupSource := fetchSource(logger, source)
+	defer cleanupSource()
+
+	if !core.HasCatalog(sourcePath) {
+		logger.Error("Path provided does not contain a template catalog", "path", sourcePath, "catalog", core.CatalogFileName)
+		core.Exit(1)
+	}
+
+	catalog, err := core.LoadCatalog(sourcePath)
+	if err != nil {
+		logger.Error("Unable to load template catalog", "catalog", core.CatalogFileName, "err", err)
+		core.Exit(1)
+	}
+
+	// Surface the metadata declared by each template configuration
+	for i, template := range catalog.Templates {
+		templatePath := filepath.Join(sourcePath, template.Path)
+		layer, err := core.LoadTemplateLayer(templatePath, filepath.Join(templatePath, core.TemplateConfigFileName))
+		if err != nil {
+			logger.Debug("No template metadata found", "template", template.Name, "err", err)
+			continue
+		}
+		catalog.Templates[i].Template = &layer.Config.Template
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		err = encoder.Encode(catalog)
+		if err != nil {
+			logger.Error("Unable to encode template catalog", "err", err)
+			core.Exit(1)
+		}
+	case "table":
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tDESCRIPTION\tVERSION\tREQUIRES\tPATH\tTAGS")
+		for _, template := range catalog.Templates {
+			version, requires := "-", "-"
+			if template.Template != nil && template.Template.Version != "" {
+				version = template.Template.Version
+			}
+			if template.Template != nil && template.Template.RequiresScaffold != "" {
+				requires = template.Template.RequiresScaffold
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", template.Name, template.Description, version, requires, template.Path, strings.Join(template.Tags, ","))
+		}
+		writer.Flush()
+	default:
+		logger.Error("Unknown output format (expected table or json)", "format", format)
+		core.Exit(1)
+	}
+}
@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func Resolve(cmd *cobra.Command, args []string) {
+	fmt.Println(resolveSource(args[0]))
+}
@@ -14,10 +14,9 @@ import (
 
 	"github.com/copito/goscaffold/core"
 	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-
-	"github.com/kluctl/go-jinja2"
 )
 
 var (
@@ -43,16 +42,38 @@ func Run(cmd *cobra.Command, args []string) {
 		runPath = args[0]
 	}
 
+	// Resolve abbreviations and fetch remote sources (the manifest records the resolved location)
+	source, err := sourceLocation(runPath)
+	if err != nil {
+		logger.Error("Unable to resolve template source", "source", runPath, "err", err)
+		core.Exit(1)
+	}
+	runPath, sourceCommit, cleanupSource := fetchSource(logger, runPath)
+	defer cleanupSource()
+
 	// Check if path exists
 	isExists, err := core.PathExists(runPath)
 	if err != nil {
 		logger.Error("Path provided does not exist...")
-		os.Exit(1)
+		core.Exit(1)
 	}
 
 	if !isExists {
 		logger.Error("Path provided does not exist...")
-		os.Exit(1)
+		core.Exit(1)
+	}
+
+	// Narrow down to the template directory inside the source (if requested)
+	directory, _ := cmd.Flags().GetString("directory")
+	if directory == "" && core.HasCatalog(runPath) {
+		directory = chooseCatalogTemplate(logger, runPath)
+	}
+
+	sourcePath := runPath
+	runPath, err = core.TemplateRootPath(runPath, directory)
+	if err != nil {
+		logger.Error("Unable to use directory as template root", "directory", directory, "err", err)
+		core.Exit(1)
 	}
 
 	// 2. Load config file
@@ -62,34 +83,52 @@ func Run(cmd *cobra.Command, args []string) {
 		configFilePath = "./config.yaml"
 	}
 
+	// Templates can ship their own configuration file (used unless one is provided)
+	if !cmd.Flags().Changed("config") {
+		templateConfigPath := path.Join(runPath, core.TemplateConfigFileName)
+		hasTemplateConfig, _ := core.PathExists(templateConfigPath)
+		if hasTemplateConfig {
+			configFilePath = templateConfigPath
+		}
+	}
+
 	extension := path.Ext(configFilePath)[1:]
-	dirConfig := path.Dir(configFilePath)
 	basePath := core.FileNameWithoutExtension(path.Base(configFilePath))
 
 	if basePath == "base" && extension == "yaml" {
 		logger.Error("base.yaml is the only name that cannot be used for the configuration file")
-		os.Exit(1)
+		core.Exit(1)
 	}
 
-	viper.SetConfigName(basePath)
-	viper.SetConfigType(extension)
-	viper.AddConfigPath(dirConfig)
+	// Parse configurations (following the extends chain of base templates)
+	layers, cleanupLayers := loadTemplateLayers(logger, runPath, configFilePath)
+	defer cleanupLayers()
+	promptConfig := core.MergePrompts(layers)
+	templateConfig := core.MergeTemplateConfigs(layers)
 
-	// Setting prefix for all env variables: SCAFFOLD_ID => viper.Get("ID")
-	// viper.SetEnvPrefix("SCAFFOLD")
+	metadata := templateConfig.Template
+	if metadata.Name != "" {
+		logger.Info("Using template", "name", metadata.Name, "version", metadata.Version, "author", metadata.Author)
+	}
 
-	err = viper.ReadInConfig()
+	// Make sure the template (and its base templates) support this scaffold version
+	scaffoldVersion := viper.GetString("global.version")
+	err = core.CheckScaffoldVersion(layers, scaffoldVersion)
 	if err != nil {
-		logger.Error("Unable to find and/or load config file", "config", configFilePath)
-		os.Exit(1)
+		logger.Error("Template is not supported by this scaffold version", "err", err)
+		core.Exit(1)
 	}
 
-	// Parse configurations
-	promptConfig := entity.Prompt{}
-	err = viper.Unmarshal(&promptConfig)
+	err = core.CheckRenderModes(layers)
 	if err != nil {
-		logger.Error("Unable to load config file", "config", configFilePath)
-		os.Exit(1)
+		logger.Error("Invalid template configuration", "err", err)
+		core.Exit(1)
+	}
+
+	err = core.CheckFormatters(templateConfig.Format)
+	if err != nil {
+		logger.Error("Invalid template configuration", "err", err)
+		core.Exit(1)
 	}
 
 	// Extract keys (for sorting index)
@@ -103,7 +142,7 @@ func Run(cmd *cobra.Command, args []string) {
 		return promptConfig.Items[keys[i]].OrderID < promptConfig.Items[keys[j]].OrderID
 	})
 
-	paramChoice := make(map[string]string)
+	paramChoice := make(map[string]any)
 
 	// ask questions about config (settle variables)
 	// Loop through all prompt based configs (based on data type
@@ -114,7 +153,13 @@ func Run(cmd *cobra.Command, args []string) {
 
 		// Private variables check (_)
 		if strings.HasPrefix(key, "_") {
-			paramChoice[key] = fmt.Sprintf("%v", item.DefaultValue)
+			switch item.DefaultValue.(type) {
+			case []interface{}, map[string]interface{}:
+				// Collections (e.g. looped over in path names) are kept as is
+				paramChoice[key] = item.DefaultValue
+			default:
+				paramChoice[key] = fmt.Sprintf("%v", item.DefaultValue)
+			}
 			continue
 		}
 
@@ -147,63 +192,419 @@ func Run(cmd *cobra.Command, args []string) {
 			result := core.BoolPrompt(logger, fmt.Sprintf("Select %s [%t]", key, item.DefaultValue), "FALSE", false)
 			paramChoice[key] = result
 			continue
+		case []interface{}:
+			// Lists of values are entered comma separated (lists of mappings keep their default)
+			if !core.IsScalarSlice(v) {
+				paramChoice[key] = v
+				continue
+			}
+			defaultValue := strings.Join(core.InterfaceSliceToStringSlice(v), ", ")
+			result := core.StringPrompt(logger, fmt.Sprintf("Select %s (comma separated) [%s]", key, defaultValue), defaultValue)
+			paramChoice[key] = core.SplitList(result)
+			continue
 		default:
 			logger.Error("unexpected type %T", v)
-			os.Exit(1)
+			core.Exit(1)
 		}
 	}
 
-	jj, err := jinja2.NewJinja2("FolderFileName", 1, jinja2.WithGlobal("scaffold", paramChoice))
+	// Template engine (jinja by default) shared by every layer of the template
+	engineName, err := core.TemplateEngineName(layers)
 	if err != nil {
-		logger.Error("Unable prepare chosen parameters for Jinja Templating...")
-		os.Exit(1)
+		logger.Error("Unable to choose template engine", "err", err)
+		core.Exit(1)
 	}
-	defer jj.Close()
+
+	workers, _ := cmd.Flags().GetInt("workers")
+	includeDirs, err := core.IncludeDirs(layers)
+	if err != nil {
+		logger.Error("Invalid includes folder", "err", err)
+		core.Exit(1)
+	}
+
+	// Undefined variables fail the render unless the template opts out
+	lenientUndefined := templateConfig.StrictUndefined != nil && !*templateConfig.StrictUndefined
+	engine, err := core.NewEngine(engineName, paramChoice, core.EngineOptions{
+		Workers:          workers,
+		IncludeDirs:      includeDirs,
+		LenientUndefined: lenientUndefined,
+	})
+	if err != nil {
+		logger.Error("Unable prepare chosen parameters for templating...", "engine", engineName, "err", err)
+		core.Exit(1)
+	}
+	defer engine.Close()
 
 	// TODO: send it to a file (if running under debug)
 	logger.Debug("New Compiled Results", "params", paramChoice)
 
-	// 3. pre-hooks
+	// Dry-runs generate the output in memory and report it instead
+	dryRunFormat, _ := cmd.Flags().GetString("dry-run")
+	err = core.CheckDryRunFormat(dryRunFormat)
+	if err != nil {
+		logger.Error("Invalid dry-run", "err", err)
+		core.Exit(1)
+	}
+	isDryRun := dryRunFormat != ""
+	outputFs := afero.NewOsFs()
+	if isDryRun {
+		outputFs = afero.NewMemMapFs()
+	}
+
+	// 3. pre-hooks (only rendered to report template errors early: running hooks is not supported yet)
 	preHookPath := path.Join(runPath, "hooks", "pre_gen_project.go")
 	hasPreGenProjectHook, _ := core.PathExists(preHookPath)
 	if hasPreGenProjectHook {
-		logger.Info("Running pre_gen_hook...")
-		err = core.RenderFileContent(preHookPath, jj)
+		logger.Info("Checking pre_gen_hook...")
+		lastLayer := layers[len(layers)-1]
+		_, err := core.RenderFileContent(preHookPath, engine, lastLayer.Config.Delimiters, lastLayer.Config.Text)
 		if err != nil {
-			logger.Error("Rendering pre-hook caused the application to crash...")
-			os.Exit(1)
+			logger.Error("Rendering pre-hook caused the application to crash...", "err", err)
+			core.Exit(1)
 		}
 	}
 
-	// 4. Generate output folder to add output here
-	isDryRun, _ := cmd.Flags().GetBool("dry-run")
-	outputFolder := "output"
-	outputBasePath := path.Join(runPath, outputFolder)
+	// 4. Output folder the project is generated in (never inside the template source)
+	outputBasePath, _ := cmd.Flags().GetString("output-dir")
+	if outputBasePath == "" {
+		outputBasePath = "."
+	}
+	templatePaths := []string{sourcePath}
+	for _, layer := range layers {
+		templatePaths = append(templatePaths, layer.RootPath)
+	}
+	for _, templatePath := range templatePaths {
+		isInsideTemplate, err := core.IsSubPath(templatePath, outputBasePath)
+		if err != nil {
+			logger.Error("Unable to resolve output folder", "output", outputBasePath, "err", err)
+			core.Exit(1)
+		}
+		if isInsideTemplate {
+			logger.Error("Output folder is inside the template source, use --output-dir to generate the project elsewhere", "output", outputBasePath, "template", templatePath)
+			core.Exit(1)
+		}
+	}
 	logger.Info(fmt.Sprintf("Output Path => %s", outputBasePath))
-	if !isDryRun {
-		err = os.Mkdir(outputBasePath, os.FileMode(0o755))
+
+	// 5. Walk through every folder/file of every template layer and render their names
+	// (files of extending templates override base files rendered to the same path)
+	plan := make(map[string]entity.PlanEntry)
+	excludedPaths := excludedConditionalPaths(logger, templateConfig, engine)
+	for _, layer := range layers {
+		deletePatterns := make([]string, 0, len(layer.Config.DeleteBaseFiles))
+		for _, pattern := range layer.Config.DeleteBaseFiles {
+			renderedPattern, err := engine.RenderString(pattern, layer.Config.Delimiters)
+			if err != nil {
+				logger.Error("Unable to render delete_base_files pattern", "pattern", pattern, "err", err)
+				core.Exit(1)
+			}
+			deletePatterns = append(deletePatterns, renderedPattern)
+		}
+
+		err = core.DeletePlanEntries(plan, deletePatterns)
 		if err != nil {
-			logger.Error("Could not create output folder")
-			os.Exit(1)
+			logger.Error("Unable to delete base template files", "template", layer.RootPath, "err", err)
+			core.Exit(1)
 		}
+
+		entries := planLayer(logger, layer, templateConfig, excludedPaths)
+		renderPlanPaths(logger, layer, entries, engine, workers, plan)
 	}
 
-	// rollback output folder
+	err = core.CheckPlanParents(plan)
+	if err != nil {
+		reportErrors(logger, "Template paths conflict with each other", err)
+		core.Exit(1)
+	}
+
+	// Rendered files can be formatted (e.g. go/format for .go files)
+	for renderedPath, entry := range plan {
+		if entry.Info.Mode().IsRegular() && !entry.CopyWithoutRender {
+			entry.Formatter = core.FileFormatter(templateConfig.Format, entry.RenderedPath)
+			plan[renderedPath] = entry
+		}
+	}
+
+	// Nothing is written until the whole plan is valid and every existing output is handled
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+	if isDryRun && !skipExisting {
+		// Dry-runs never ask, existing files are reported as changed (unless kept)
+		overwrite = true
+	}
+	createdPaths, existingPaths := resolveExistingOutputs(logger, plan, outputBasePath, templateConfig, engine, overwrite, skipExisting)
+
+	// The manifest follows the same policy (without diff as it depends on the generated files)
+	manifestPath, err := core.ManifestPath(templateConfig.Manifest)
+	if err != nil {
+		logger.Error("Invalid manifest configuration", "err", err)
+		core.Exit(1)
+	}
+	projectRoot := core.ProjectRoot(plan)
+	manifestRenderedPath := path.Join(projectRoot, manifestPath)
+	if manifestPath != "" {
+		manifestAction := resolveExistingManifest(logger, manifestRenderedPath, outputBasePath, templateConfig, overwrite, skipExisting)
+		switch manifestAction {
+		case core.ConflictKeep:
+			manifestPath = ""
+		case core.ConflictWriteNew:
+			manifestRenderedPath += core.NewFileSuffix
+		}
+	}
+
+	isOutputExists, err := core.PathExists(outputBasePath)
+	if err != nil {
+		logger.Error("Unable to access output folder", "output", outputBasePath, "err", err)
+		core.Exit(1)
+	}
+	if !isOutputExists {
+		err = outputFs.MkdirAll(outputBasePath, os.FileMode(0o755))
+		if err != nil {
+			logger.Error("Could not create output folder", "output", outputBasePath, "err", err)
+			core.Exit(1)
+		}
+		createdPaths = []string{outputBasePath}
+	}
+
+	// Files and symlinks are staged next to their output path and only replace it once everything is rendered
+	transaction := core.NewOutputTransaction(outputFs)
+
+	// rollback generated paths (the output folder may hold other files, only the ones created by this run are
+	// removed and the replaced ones restored)
 	rollbackChan := make(chan interface{}, 1)
-	rollbackOutput := func(rollbackChan chan interface{}, outputBasePath string) {
+	rollbackOutput := func(rollbackChan chan interface{}) {
 		defer close(rollbackChan)
 		<-rollbackChan
 
-		logger.Info("Invoked rollback - removing output folder...")
-		err := os.RemoveAll(outputBasePath)
+		logger.Info("Invoked rollback - removing generated paths...")
+		err := transaction.Rollback()
+		if err != nil {
+			logger.Error("Error restoring replaced paths...", "err", err)
+		}
+		for _, createdPath := range createdPaths {
+			err := outputFs.RemoveAll(createdPath)
+			if err != nil {
+				logger.Error("Error cleaning up output folder...", "path", createdPath)
+				core.Exit(1)
+			}
+		}
+	}
+	go rollbackOutput(rollbackChan)
+
+	// 6. Create every folder and stage every symlink of the plan (parents first) and collect the files to generate
+	folders := []entity.PlanEntry{}
+	files := []entity.PlanEntry{}
+	stagedFiles := []string{}
+	for _, entry := range core.SortedPlan(plan) {
+		newFullPathRendered := path.Join(outputBasePath, entry.RenderedPath)
+		if entry.Kept {
+			continue
+		}
+
+		switch mode := entry.Info.Mode(); {
+		case mode.IsDir():
+			// Existing folders are merged (keeping their permissions)
+			if existingPaths[entry.RenderedPath] {
+				continue
+			}
+
+			// Folder/Directory
+			// Create folder
+			err = outputFs.MkdirAll(newFullPathRendered, os.FileMode(0o755))
+			if err != nil {
+				rollbackChan <- true
+				time.Sleep(time.Second)
+				core.Exit(1)
+			}
+			folders = append(folders, entry)
+
+		case mode&os.ModeSymlink != 0:
+			// Symlink (pointing to the rendered target), only reported by dry-runs
+			if isDryRun {
+				continue
+			}
+			err = os.Symlink(entry.LinkTarget, transaction.Stage(newFullPathRendered))
+			if err != nil {
+				logger.Error("failed to create symlink", "path", newFullPathRendered, "target", entry.LinkTarget, "err", err)
+				rollbackChan <- true
+				time.Sleep(time.Second)
+				core.Exit(1)
+			}
+
+		case mode.IsRegular():
+			files = append(files, entry)
+			stagedFiles = append(stagedFiles, transaction.Stage(newFullPathRendered))
+		}
+	}
+
+	// 7. Render the files concurrently (writing every staged file once), then replace the outputs
+	err = core.ParallelFor(workers, len(files), func(i int) error {
+		entry := files[i]
+		bytesProcessed, err := core.GenerateFile(outputFs, entry, stagedFiles[i], engine)
+		if err != nil {
+			return err
+		}
+		logger.Debug("Generated file", "file", entry.SourcePath, "bytes", bytesProcessed)
+		return nil
+	})
+	if err != nil {
+		reportErrors(logger, "Rendering template files failed", err)
+		rollbackChan <- true
+		time.Sleep(time.Second)
+		core.Exit(1)
+	}
+
+	err = transaction.Apply()
+	if err != nil {
+		logger.Error("Unable to replace output paths", "err", err)
+		rollbackChan <- true
+		time.Sleep(time.Second)
+		core.Exit(1)
+	}
+
+	// 8. Manifest recording how the project was generated (inside the generated project)
+	extraFiles := []string{}
+	if manifestPath != "" {
+		generatedFiles, err := core.ManifestFiles(outputFs, plan, outputBasePath, projectRoot)
+		if err != nil {
+			logger.Error("Unable to hash generated files", "err", err)
+			rollbackChan <- true
+			time.Sleep(time.Second)
+			core.Exit(1)
+		}
+
+		manifest := entity.Manifest{
+			Template: entity.ManifestTemplate{
+				Source:    source,
+				Directory: directory,
+				Commit:    sourceCommit,
+				Name:      templateConfig.Template.Name,
+				Version:   templateConfig.Template.Version,
+			},
+			ScaffoldVersion: scaffoldVersion,
+			GeneratedAt:     time.Now().UTC().Format(time.RFC3339),
+			Answers:         core.ManifestAnswers(promptConfig, paramChoice),
+			Files:           generatedFiles,
+		}
+
+		// Without a project folder the manifest folder is created at the root of the output
+		manifestFullPath := path.Join(outputBasePath, manifestRenderedPath)
+		if projectRoot == "" {
+			manifestRoot := path.Join(outputBasePath, strings.Split(path.Clean(manifestPath), "/")[0])
+			isExists, _ := core.PathExists(manifestRoot)
+			if !isExists && isOutputExists {
+				createdPaths = append(createdPaths, manifestRoot)
+			}
+		}
+
+		err = core.WriteManifest(outputFs, transaction.Stage(manifestFullPath), manifest)
+		if err == nil {
+			err = transaction.Apply()
+		}
 		if err != nil {
-			logger.Error("Error cleaning up output folder...")
-			os.Exit(1)
+			logger.Error("Unable to write generation manifest", "err", err)
+			rollbackChan <- true
+			time.Sleep(time.Second)
+			core.Exit(1)
 		}
+		extraFiles = append(extraFiles, manifestRenderedPath)
+	}
+
+	// 9. Folder permissions are set last (read-only folders would prevent generating their files)
+	for i := len(folders) - 1; i >= 0; i-- {
+		err = outputFs.Chmod(path.Join(outputBasePath, folders[i].RenderedPath), folders[i].Mode)
+		if err != nil {
+			logger.Error("failed to set folder permissions", "path", folders[i].RenderedPath, "err", err)
+			rollbackChan <- true
+			time.Sleep(time.Second)
+			core.Exit(1)
+		}
+	}
+
+	// Replaced paths are not needed anymore
+	err = transaction.Commit()
+	if err != nil {
+		logger.Warn("Unable to remove backups of replaced paths", "err", err)
+	}
+
+	// Dry-runs report what would be generated instead (nothing was written to disk)
+	if isDryRun {
+		reportDryRun(logger, outputFs, plan, outputBasePath, extraFiles, dryRunFormat)
+		return
+	}
+
+	// 10. TODO: post-hook
+	hasPostGenProjectHook, _ := core.PathExists(path.Join(runPath, "hooks", "post_gen_project.go"))
+	if hasPostGenProjectHook {
+		fmt.Println("Running post_gen_project...")
+	}
+
+	logger.Info("🚀🚀 Scaffold ran successfully! 🚀🚀")
+}
+
+// reportDryRun prints the paths a dry-run generated in memory, compared to the output folder on disk
+func reportDryRun(logger *slog.Logger, outputFs afero.Fs, plan map[string]entity.PlanEntry, outputBasePath string, extraFiles []string, format string) {
+	entries, err := core.DryRunEntries(outputFs, plan, outputBasePath, extraFiles)
+	if err != nil {
+		logger.Error("Unable to compare dry-run with the output folder", "err", err)
+		core.Exit(1)
+	}
+
+	if format == core.DryRunJSON {
+		report, err := core.FormatDryRunJSON(outputBasePath, entries)
+		if err != nil {
+			logger.Error("Unable to format dry-run", "err", err)
+			core.Exit(1)
+		}
+		fmt.Print(report)
+		return
+	}
+	fmt.Print(core.FormatDryRunTree(outputBasePath, entries))
+}
+
+// chooseCatalogTemplate asks which template of the catalog should be generated
+// and returns its directory inside the template source
+func chooseCatalogTemplate(logger *slog.Logger, sourcePath string) string {
+	catalog, err := core.LoadCatalog(sourcePath)
+	if err != nil {
+		logger.Error("Unable to load template catalog", "catalog", core.CatalogFileName, "err", err)
+		core.Exit(1)
+	}
+
+	if len(catalog.Templates) == 0 {
+		logger.Error("Template catalog does not list any template", "catalog", core.CatalogFileName)
+		core.Exit(1)
+	}
+
+	names := make([]string, 0, len(catalog.Templates))
+	for _, template := range catalog.Templates {
+		names = append(names, template.Name)
+	}
+
+	result := core.SingleSelectPrompt(logger, "Select template", names)
+	template, _ := core.FindCatalogTemplate(catalog, result)
+	logger.Info("Using template from catalog", "name", template.Name, "path", template.Path)
+
+	return template.Path
+}
+
+// planLayer walks through every folder/file of a template layer and returns the ones
+// to generate (their names are rendered afterwards by renderPlanPaths)
+func planLayer(logger *slog.Logger, layer entity.TemplateLayer, templateConfig entity.TemplateConfig, excludedPaths []string) []entity.PlanEntry {
+	runPath := layer.RootPath
+	entries := []entity.PlanEntry{}
+	includesDir, err := core.IncludesDir(layer.Config)
+	if err != nil {
+		logger.Error("Invalid includes folder", "template", runPath, "err", err)
+		core.Exit(1)
+	}
+
+	ignoreMatcher, err := core.LoadIgnoreMatcher(runPath)
+	if err != nil {
+		logger.Error("Unable to load ignore file", "file", path.Join(runPath, core.IgnoreFileName), "err", err)
+		core.Exit(1)
 	}
-	go rollbackOutput(rollbackChan, outputBasePath)
 
-	// 5. Walk through every folder/file and change names + file data
 	err = filepath.Walk(runPath, func(pathValue string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -216,8 +617,36 @@ func Run(cmd *cobra.Command, args []string) {
 			return nil
 		}
 
+		// Skip - Ignored paths (ignored folders are not walked at all)
+		deltaPath := core.DeltaRelativePath(runPath, pathValue)
+		if deltaPath == core.IgnoreFileName || core.IsIgnored(ignoreMatcher, deltaPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Skip - Shared includes (only used by include/import/extends)
+		if deltaPath == includesDir && info.IsDir() {
+			return filepath.SkipDir
+		}
+
+		// Skip - Conditional paths whose condition is not met
+		isExcluded, err := core.MatchAnyGlob(excludedPaths, deltaPath)
+		if err != nil {
+			logger.Error("Invalid conditional_paths pattern", "err", err)
+			core.Exit(1)
+		}
+		if isExcluded {
+			logger.Debug("Skipping conditional path", "templated", deltaPath)
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// Skip - Bypass config file
-		if path.Base(pathValue) == path.Base(configFilePath) {
+		if path.Base(pathValue) == path.Base(layer.ConfigPath) {
 			// Skip configuration file from walk
 			return nil
 		}
@@ -236,71 +665,352 @@ func Run(cmd *cobra.Command, args []string) {
 			return nil
 		}
 
-		// Skip Output created folder
-		if strings.HasPrefix(pathValue, outputBasePath) {
-			// If it being outputted to the output file then
-			// it should be ignored (especially to avoid recursive loop)
-			return nil
-		}
-
-		// Copy file to output folder -> also transforming using Jinja2
 		logger.Info(pathValue, "size", info.Size(), "is_dir", info.Mode().IsDir(), "is_file", info.Mode().IsRegular())
-		deltaPath := core.DeltaRelativePath(runPath, pathValue)
-		newFullPath := path.Join(outputBasePath, deltaPath)
 
-		// Jinja template path name
-		newFullPathRendered, err := jj.RenderString(newFullPath)
+		// Files (or whole folders) that must be copied byte-for-byte
+		copyWithoutRender, err := core.MatchAnyGlobOrParent(templateConfig.CopyWithoutRender, deltaPath)
 		if err != nil {
-			// rollbackChan <- true
-			fmt.Println("rendered new path name but failed!!")
-			os.Exit(1)
+			logger.Error("Invalid copy_without_render pattern", "err", err)
+			core.Exit(1)
+		}
+		if copyWithoutRender && info.Mode().IsRegular() {
+			logger.Debug("Not rendering file", "file", deltaPath, "reason", "copy_without_render pattern")
 		}
 
-		logger.Info("jinja template", "templated", newFullPath, "rendered", newFullPathRendered)
+		// Only files with a template suffix (main.go.j2) are rendered when using the suffix render mode
+		if !copyWithoutRender && info.Mode().IsRegular() && layer.Config.RenderMode == core.RenderModeSuffix {
+			if _, marked := core.TrimTemplateSuffix(deltaPath); !marked {
+				logger.Debug("Not rendering file", "file", deltaPath, "reason", "no template suffix (render_mode: suffix)")
+				copyWithoutRender = true
+			}
+		}
 
-		switch mode := info.Mode(); {
-		case mode.IsDir():
-			// Folder/Directory
-			// Create folder
-			err = os.MkdirAll(newFullPathRendered, os.FileMode(0o755))
+		// Line endings, trailing newline, BOM and encoding of the file
+		text, err := core.FileTextOptions(layer.Config, deltaPath)
+		if err != nil {
+			logger.Error("Invalid text options", "file", deltaPath, "err", err)
+			core.Exit(1)
+		}
+
+		// Binary files (images, fonts, archives...) are copied unchanged unless forced to render
+		isBinary := false
+		if info.Mode().IsRegular() {
+			forceRender, err := core.MatchAnyGlobOrParent(templateConfig.ForceRender, deltaPath)
 			if err != nil {
-				rollbackChan <- true
-				time.Sleep(time.Second)
-				os.Exit(1)
+				logger.Error("Invalid force_render pattern", "err", err)
+				core.Exit(1)
 			}
 
-		case mode.IsRegular():
-			// File
-			bytesProcessed, err := core.PathCopy(pathValue, newFullPathRendered)
+			binaryContent, reason, err := core.DetectBinaryFile(pathValue, text.Encoding)
 			if err != nil {
-				logger.Error("failed to copy file to output path", "err", err)
-				rollbackChan <- true
-				time.Sleep(time.Second)
-				os.Exit(1)
+				logger.Error("Unable to read template file", "file", pathValue, "err", err)
+				core.Exit(1)
+			}
+
+			isBinary = binaryContent && !forceRender
+			if isBinary && !copyWithoutRender {
+				logger.Debug("Not rendering file", "file", deltaPath, "reason", reason)
+				copyWithoutRender = true
 			}
-			logger.Debug("Processed file copy", "file", pathValue, "bytes", bytesProcessed)
+		}
+
+		delimiters, err := core.FileDelimiters(layer.Config, deltaPath)
+		if err != nil {
+			logger.Error("Invalid delimiter_overrides pattern", "err", err)
+			core.Exit(1)
+		}
+
+		// Permissions of the template path (unless overridden in the configuration)
+		mode, err := core.OutputFileMode(templateConfig, deltaPath, info.Mode())
+		if err != nil {
+			logger.Error("Invalid file mode configuration", "err", err)
+			core.Exit(1)
+		}
 
-			// Render this file content
-			err = core.RenderFileContent(newFullPathRendered, jj)
+		// Symlinks are recreated (their target is rendered like path names)
+		linkTarget := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			linkTarget, err = os.Readlink(pathValue)
 			if err != nil {
-				fmt.Println("rendering file (using jinja) failed!!")
-				rollbackChan <- true
-				time.Sleep(time.Second)
-				os.Exit(1)
+				logger.Error("Unable to read template symlink", "file", pathValue, "err", err)
+				core.Exit(1)
 			}
 		}
 
+		entries = append(entries, entity.PlanEntry{
+			SourcePath:        pathValue,
+			TemplatePath:      deltaPath,
+			Info:              info,
+			Mode:              mode,
+			LinkTarget:        linkTarget,
+			CopyWithoutRender: copyWithoutRender,
+			Binary:            isBinary,
+			Delimiters:        delimiters,
+			Text:              text,
+		})
+
 		return nil
 	})
 	if err != nil {
 		log.Println(err)
 	}
 
-	// 6. TODO: post-hook
-	hasPostGenProjectHook, _ := core.PathExists(path.Join(runPath, "hooks", "post_gen_project.go"))
-	if hasPostGenProjectHook {
-		fmt.Println("Running post_gen_project...")
+	return entries
+}
+
+// renderPlanPaths renders the path names of a template layer's entries concurrently and adds
+// them to the plan (overriding entries previously rendered to the same path)
+func renderPlanPaths(logger *slog.Logger, layer entity.TemplateLayer, layerEntries []entity.PlanEntry, engine core.Engine, workers int, plan map[string]entity.PlanEntry) {
+	// Path loops ([[item in scaffold.items]]{{ item.name }}.go) fan out into one entry per element
+	iterations := make([][]core.PathIteration, len(layerEntries))
+	err := core.ParallelFor(workers, len(layerEntries), func(i int) error {
+		templatePath := layerEntries[i].TemplatePath
+		if !core.HasPathLoop(templatePath) {
+			iterations[i] = []core.PathIteration{{TemplatePath: templatePath}}
+			return nil
+		}
+
+		var err error
+		iterations[i], err = core.ExpandPathLoops(templatePath, engine)
+		if err != nil {
+			return core.WithTemplateFile(err, templatePath)
+		}
+		return nil
+	})
+	if err != nil {
+		reportErrors(logger, "Unable to expand template path loops", err)
+		core.Exit(1)
 	}
 
-	logger.Info("🚀🚀 Scaffold ran successfully! 🚀🚀")
+	entries := []entity.PlanEntry{}
+	pathTemplates := []string{}
+	for i, entry := range layerEntries {
+		for _, iteration := range iterations[i] {
+			entry.Vars = iteration.Vars
+			entries = append(entries, entry)
+			pathTemplates = append(pathTemplates, iteration.TemplatePath)
+		}
+	}
+
+	// Template path names and symlink targets (delimiters are the ones of the layer the file comes from)
+	err = core.ParallelFor(workers, len(entries), func(i int) error {
+		rendered, err := engine.RenderStringWith(pathTemplates[i], layer.Config.Delimiters, entries[i].Vars)
+		if err != nil {
+			return core.WithTemplateFile(err, entries[i].TemplatePath)
+		}
+		// The template suffix is not part of the generated file name (main.go.j2 -> main.go)
+		if layer.Config.RenderMode == core.RenderModeSuffix && entries[i].Info.Mode().IsRegular() {
+			rendered, _ = core.TrimTemplateSuffix(rendered)
+		}
+		entries[i].RenderedPath = rendered
+
+		// Skipped later on (see below), other paths must stay inside the output folder
+		rendered, skipped, err := core.RenderedOutputPath(pathTemplates[i], rendered)
+		if err != nil {
+			return core.WithTemplateFile(err, entries[i].TemplatePath)
+		}
+		if skipped {
+			return nil
+		}
+		entries[i].RenderedPath = rendered
+
+		if entries[i].Info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+
+		if !entries[i].CopyWithoutRender {
+			entries[i].LinkTarget, err = engine.RenderStringWith(entries[i].LinkTarget, layer.Config.Delimiters, entries[i].Vars)
+			if err != nil {
+				return core.WithTemplateFile(err, entries[i].TemplatePath+" (symlink target)")
+			}
+		}
+		return core.CheckSymlinkTarget(rendered, entries[i].LinkTarget)
+	})
+	if err != nil {
+		reportErrors(logger, "Unable to render template path names", err)
+		core.Exit(1)
+	}
+
+	generatedEntries := []entity.PlanEntry{}
+	for _, entry := range entries {
+		logger.Info("template path", "templated", entry.TemplatePath, "rendered", entry.RenderedPath)
+
+		// Skip - Paths rendered with an empty segment (e.g. {% if scaffold.use_docker %}docker{% endif %}),
+		// along with everything inside them as their paths contain the same segment
+		if core.HasEmptyPathSegment(entry.RenderedPath) {
+			logger.Debug("Skipping path rendered empty", "templated", entry.TemplatePath, "rendered", entry.RenderedPath)
+			continue
+		}
+
+		generatedEntries = append(generatedEntries, entry)
+	}
+
+	err = core.CheckPathCollisions(generatedEntries)
+	if err != nil {
+		reportErrors(logger, "Template paths render to the same output path", err)
+		core.Exit(1)
+	}
+
+	for _, entry := range generatedEntries {
+		plan[entry.RenderedPath] = entry
+	}
+}
+
+// resolveExistingOutputs handles the generated paths already in the output folder before anything is written:
+// folders are merged while existing files are overwritten, kept or generated next to them (.new) following
+// skip_if_exists, --overwrite/--skip-existing or the choice of the user. It returns the highest paths created by
+// this run (removed on rollback) along with the existing paths still generated (files are replaced)
+func resolveExistingOutputs(logger *slog.Logger, plan map[string]entity.PlanEntry, outputBasePath string, templateConfig entity.TemplateConfig, engine core.Engine, overwrite bool, skipExisting bool) ([]string, map[string]bool) {
+	createdPaths := []string{}
+	existingPaths := map[string]bool{}
+	for _, entry := range core.SortedPlan(plan) {
+		fullPath := path.Join(outputBasePath, entry.RenderedPath)
+		isExists, err := core.ExistingOutput(entry, fullPath)
+		if err != nil {
+			logger.Error("Unable to generate over existing output", "path", entry.RenderedPath, "err", err)
+			core.Exit(1)
+		}
+
+		if !isExists {
+			if parent := path.Dir(entry.RenderedPath); parent == "." || existingPaths[parent] {
+				createdPaths = append(createdPaths, fullPath)
+			}
+			continue
+		}
+
+		if entry.Info.IsDir() {
+			existingPaths[entry.RenderedPath] = true
+			continue
+		}
+
+		action, err := core.ConflictAction(templateConfig.SkipIfExists, entry.RenderedPath, overwrite, skipExisting)
+		if err != nil {
+			logger.Error("Unable to handle existing output", "path", entry.RenderedPath, "err", err)
+			core.Exit(1)
+		}
+		for action == "" || action == core.ConflictShowDiff {
+			if action == core.ConflictShowDiff {
+				showOutputDiff(logger, entry, fullPath, engine)
+			}
+			action = core.SingleSelectPrompt(logger, fmt.Sprintf("%s already exists", fullPath), core.ConflictActions)
+		}
+		logger.Info("Existing output", "path", entry.RenderedPath, "action", action)
+
+		switch action {
+		case core.ConflictOverwrite:
+			existingPaths[entry.RenderedPath] = true
+
+		case core.ConflictKeep:
+			entry.Kept = true
+			plan[entry.RenderedPath] = entry
+
+		case core.ConflictWriteNew:
+			delete(plan, entry.RenderedPath)
+			entry.RenderedPath += core.NewFileSuffix
+			if _, found := plan[entry.RenderedPath]; found {
+				logger.Error("Template already generates the path", "path", entry.RenderedPath)
+				core.Exit(1)
+			}
+
+			isNewExists, err := core.ExistingOutput(entry, path.Join(outputBasePath, entry.RenderedPath))
+			if err != nil {
+				logger.Error("Unable to generate over existing output", "path", entry.RenderedPath, "err", err)
+				core.Exit(1)
+			}
+			existingPaths[entry.RenderedPath] = isNewExists
+			plan[entry.RenderedPath] = entry
+		}
+	}
+	return createdPaths, existingPaths
+}
+
+// resolveExistingManifest returns how to write the manifest when it already exists in the output folder
+// (skip_if_exists and --overwrite/--skip-existing apply as for generated files)
+func resolveExistingManifest(logger *slog.Logger, manifestRenderedPath string, outputBasePath string, templateConfig entity.TemplateConfig, overwrite bool, skipExisting bool) string {
+	fullPath := path.Join(outputBasePath, manifestRenderedPath)
+	isExists, err := core.PathExists(fullPath)
+	if err != nil {
+		logger.Error("Unable to access existing manifest", "path", fullPath, "err", err)
+		core.Exit(1)
+	}
+	if !isExists {
+		return core.ConflictOverwrite
+	}
+
+	action, err := core.ConflictAction(templateConfig.SkipIfExists, manifestRenderedPath, overwrite, skipExisting)
+	if err != nil {
+		logger.Error("Unable to handle existing output", "path", manifestRenderedPath, "err", err)
+		core.Exit(1)
+	}
+	if action == "" {
+		// Its content depends on the generated files, so it can not be compared beforehand
+		action = core.SingleSelectPrompt(logger, fmt.Sprintf("%s already exists", fullPath), []string{core.ConflictOverwrite, core.ConflictKeep, core.ConflictWriteNew})
+	}
+	logger.Info("Existing output", "path", manifestRenderedPath, "action", action)
+	return action
+}
+
+// showOutputDiff prints the changes generating a file would make to the existing one
+func showOutputDiff(logger *slog.Logger, entry entity.PlanEntry, fullPath string, engine core.Engine) {
+	existing, generated := "", ""
+	switch {
+	case entry.Info.Mode()&os.ModeSymlink != 0:
+		target, _ := os.Readlink(fullPath)
+		existing, generated = "-> "+target+"\n", "-> "+entry.LinkTarget+"\n"
+
+	case entry.Binary:
+		fmt.Printf("%s is a binary file\n", entry.RenderedPath)
+		return
+
+	default:
+		existingData, err := os.ReadFile(fullPath)
+		if err != nil {
+			logger.Error("Unable to read existing file", "path", fullPath, "err", err)
+			return
+		}
+		generatedData, err := core.RenderFile(entry, engine)
+		if err != nil {
+			reportErrors(logger, "Unable to render template file", err)
+			core.Exit(1)
+		}
+		existing, generated = string(existingData), string(generatedData)
+	}
+
+	diff := core.LineDiff(entry.RenderedPath, existing, generated)
+	if diff == "" {
+		fmt.Printf("%s is unchanged\n", entry.RenderedPath)
+		return
+	}
+	fmt.Print(diff)
+}
+
+// excludedConditionalPaths evaluates the conditional paths of the template and
+// returns the glob patterns of paths that must not be generated
+func excludedConditionalPaths(logger *slog.Logger, templateConfig entity.TemplateConfig, engine core.Engine) []string {
+	excludedPaths := []string{}
+	for _, conditionalPath := range templateConfig.ConditionalPaths {
+		isIncluded, err := engine.EvaluateCondition(conditionalPath.When)
+		if err != nil {
+			logger.Error("Unable to evaluate conditional path", "path", conditionalPath.Path, "when", conditionalPath.When, "err", err)
+			core.Exit(1)
+		}
+
+		if !isIncluded {
+			excludedPaths = append(excludedPaths, conditionalPath.Path)
+		}
+	}
+	return excludedPaths
+}
+
+// reportErrors logs a failure along with every error it aggregates (e.g. one per broken template file)
+func reportErrors(logger *slog.Logger, message string, err error) {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	logger.Error(message, "errors", len(errs))
+	for _, e := range errs {
+		fmt.Fprintln(os.Stderr, e)
+	}
 }
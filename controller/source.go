@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/spf13/viper"
+)
+
+// resolveSource expands abbreviations found in the template source
+func resolveSource(source string) string {
+	abbreviations := core.SourceAbbreviations(viper.GetStringMapString("abbreviations"))
+	return core.ResolveSource(source, abbreviations)
+}
+
+// sourceLocation returns the resolved location of the template source (repository URL or absolute path)
+func sourceLocation(source string) (string, error) {
+	abbreviations := core.SourceAbbreviations(viper.GetStringMapString("abbreviations"))
+	return core.SourceLocation(source, abbreviations)
+}
+
+// fetchSource makes the template source available locally (cloning remote repositories)
+// and returns its local path, its git commit (if any) along with a cleanup function
+func fetchSource(logger *slog.Logger, source string) (string, string, func()) {
+	location := resolveSource(source)
+	if location != source {
+		logger.Info("Resolved template source", "source", source, "location", location)
+	}
+
+	if !core.IsRepositoryURL(location) {
+		return location, core.RepositoryCommit(location), func() {}
+	}
+
+	clonePath, commit, cleanup, err := cloneSource(logger, location)
+	if err != nil {
+		logger.Error("Unable to clone template source", "location", location, "err", err)
+		core.Exit(1)
+	}
+	return clonePath, commit, cleanup
+}
+
+// cloneSource clones a template repository into a temporary folder and returns its path,
+// its git commit along with a cleanup function removing it
+func cloneSource(logger *slog.Logger, location string) (string, string, func(), error) {
+	clonePath, err := os.MkdirTemp("", "scaffold-source-")
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	logger.Info("Cloning template source...", "location", location)
+	commit, err := core.CloneRepository(location, clonePath)
+	if err != nil {
+		os.RemoveAll(clonePath)
+		return "", "", nil, err
+	}
+	logger.Debug("Cloned template source", "location", location, "commit", commit)
+
+	// Also removed when exiting on errors
+	cleanup := core.OnExit(func() {
+		os.RemoveAll(clonePath)
+	})
+	return clonePath, commit, cleanup, nil
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"log/slog"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/viper"
+)
+
+// loadTemplateLayers follows the extends chain of a template and returns every layer
+// (base templates first) along with a cleanup function for fetched base templates
+func loadTemplateLayers(logger *slog.Logger, rootPath string, configPath string) ([]entity.TemplateLayer, func()) {
+	abbreviations := core.SourceAbbreviations(viper.GetStringMapString("abbreviations"))
+	fetchBase := func(location string) (string, func(), error) {
+		basePath, _, cleanup, err := cloneSource(logger, location)
+		return basePath, cleanup, err
+	}
+
+	layers, cleanup, err := core.LoadTemplateLayers(rootPath, configPath, abbreviations, fetchBase)
+	if err != nil {
+		logger.Error("Unable to load template configuration", "err", err)
+		core.Exit(1)
+	}
+
+	for i := len(layers) - 1; i > 0; i-- {
+		logger.Info("Extending base template", "template", layers[i].RootPath, "extends", layers[i-1].RootPath)
+	}
+	return layers, cleanup
+}
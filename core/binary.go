@@ -0,0 +1,85 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// binarySniffLength is how many bytes are read from a file to detect binary content
+const binarySniffLength = 8000
+
+// BinaryExtensions are file extensions always considered binary content
+var BinaryExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true, ".ico": true,
+	".icns": true, ".webp": true, ".tif": true, ".tiff": true, ".psd": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".zip": true, ".gz": true, ".tgz": true, ".bz2": true, ".xz": true, ".7z": true, ".rar": true, ".tar": true,
+	".jar": true, ".war": true, ".ear": true, ".class": true,
+	".exe": true, ".dll": true, ".so": true, ".dylib": true, ".a": true, ".o": true, ".lib": true,
+	".pyc": true, ".wasm": true, ".bin": true, ".dat": true, ".db": true, ".sqlite": true,
+	".pdf": true, ".mp3": true, ".mp4": true, ".wav": true, ".ogg": true, ".mov": true, ".avi": true,
+}
+
+// DetectBinaryFile returns whether a file holds binary content along with the reason why
+// (files with another encoding than UTF-8 are not expected to hold valid UTF-8)
+func DetectBinaryFile(src string, encoding string) (bool, string, error) {
+	extension := strings.ToLower(filepath.Ext(src))
+	if BinaryExtensions[extension] {
+		return true, "binary extension " + extension, nil
+	}
+
+	source, err := os.Open(src)
+	if err != nil {
+		return false, "", err
+	}
+	defer source.Close()
+
+	data := make([]byte, binarySniffLength)
+	n, err := io.ReadFull(source, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, "", err
+	}
+
+	textEncoding, err := TextEncoding(encoding)
+	if err != nil {
+		return false, "", err
+	}
+	if textEncoding != nil {
+		if bytes.IndexByte(data[:n], 0) != -1 {
+			return true, "contains NUL bytes", nil
+		}
+		return false, "", nil
+	}
+
+	isBinary, reason := DetectBinaryContent(data[:n], n == binarySniffLength)
+	return isBinary, reason, nil
+}
+
+// DetectBinaryContent sniffs data for NUL bytes and invalid UTF-8 (truncated tells
+// whether data is only the beginning of the content)
+func DetectBinaryContent(data []byte, truncated bool) (bool, string) {
+	if bytes.IndexByte(data, 0) != -1 {
+		return true, "contains NUL bytes"
+	}
+
+	// A multi-byte character could have been cut at the end of the sniffed data
+	if truncated {
+		for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					data = data[:i]
+				}
+				break
+			}
+		}
+	}
+
+	if !utf8.Valid(data) {
+		return true, "invalid UTF-8 content"
+	}
+	return false, ""
+}
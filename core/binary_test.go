@@ -0,0 +1,33 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestDetectBinaryContent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      []byte
+		truncated bool
+		expected  bool
+	}{
+		{name: "text", data: []byte("package main\n\nfunc main() {}\n"), expected: false},
+		{name: "utf8 text", data: []byte("héllo wörld ✓"), expected: false},
+		{name: "nul bytes", data: []byte("PK\x03\x04\x00\x00"), expected: true},
+		{name: "latin-1", data: []byte("caf\xe9"), expected: true},
+		{name: "cut multi-byte character", data: []byte("check \xe2\x9c"), truncated: true, expected: false},
+		{name: "incomplete multi-byte character", data: []byte("check \xe2\x9c"), truncated: false, expected: true},
+		{name: "empty", data: []byte{}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, reason := core.DetectBinaryContent(tc.data, tc.truncated)
+			if actual != tc.expected {
+				t.Errorf("DetectBinaryContent(%q) = %t (%s), expected %t", tc.data, actual, reason, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/viper"
+)
+
+// CatalogFileName is the manifest listing every template available in a source
+const CatalogFileName = "scaffold-catalog.yaml"
+
+// HasCatalog returns whether the template source contains a catalog manifest
+func HasCatalog(sourcePath string) bool {
+	isExists, err := PathExists(filepath.Join(sourcePath, CatalogFileName))
+	return err == nil && isExists
+}
+
+// LoadCatalog parses the catalog manifest found at the root of the template source
+func LoadCatalog(sourcePath string) (*entity.Catalog, error) {
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(sourcePath, CatalogFileName))
+
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := entity.Catalog{}
+	err = v.Unmarshal(&catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(catalog.Templates))
+	for i, template := range catalog.Templates {
+		if template.Name == "" {
+			return nil, fmt.Errorf("catalog template #%d has no name", i+1)
+		}
+		if template.Path == "" {
+			return nil, fmt.Errorf("catalog template %s has no path", template.Name)
+		}
+		if seen[template.Name] {
+			return nil, fmt.Errorf("catalog template %s is declared more than once", template.Name)
+		}
+		seen[template.Name] = true
+	}
+
+	return &catalog, nil
+}
+
+// FindCatalogTemplate looks up a catalog template by its name
+func FindCatalogTemplate(catalog *entity.Catalog, name string) (entity.CatalogTemplate, bool) {
+	for _, template := range catalog.Templates {
+		if template.Name == name {
+			return template, true
+		}
+	}
+	return entity.CatalogTemplate{}, false
+}
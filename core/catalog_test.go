@@ -0,0 +1,118 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	testCases := []struct {
+		name      string
+		catalog   string
+		expected  *entity.Catalog
+		expectErr bool
+	}{
+		{
+			name: "templates",
+			catalog: "templates:\n" +
+				"  - name: go-service\n" +
+				"    description: HTTP service written in Go\n" +
+				"    path: templates/go-service\n" +
+				"    tags: [go, service]\n" +
+				"  - name: go-lib\n" +
+				"    path: templates/go-lib\n",
+			expected: &entity.Catalog{Templates: []entity.CatalogTemplate{
+				{Name: "go-service", Description: "HTTP service written in Go", Path: "templates/go-service", Tags: []string{"go", "service"}},
+				{Name: "go-lib", Path: "templates/go-lib"},
+			}},
+		},
+		{
+			name:      "missing name",
+			catalog:   "templates:\n  - path: templates/go-lib\n",
+			expectErr: true,
+		},
+		{
+			name:      "missing path",
+			catalog:   "templates:\n  - name: go-lib\n",
+			expectErr: true,
+		},
+		{
+			name: "duplicate names",
+			catalog: "templates:\n" +
+				"  - name: go-lib\n    path: templates/go-lib\n" +
+				"  - name: go-lib\n    path: templates/go-lib-v2\n",
+			expectErr: true,
+		},
+		{
+			name:      "invalid yaml",
+			catalog:   "templates: [\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sourcePath := t.TempDir()
+			writeTestFiles(t, sourcePath, map[string]string{core.CatalogFileName: tc.catalog})
+
+			if !core.HasCatalog(sourcePath) {
+				t.Fatalf("HasCatalog(%q) = false, expected true", sourcePath)
+			}
+
+			actual, err := core.LoadCatalog(sourcePath)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("LoadCatalog expected an error, got %v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadCatalog failed: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("LoadCatalog = %+v, expected %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLoadCatalogMissing(t *testing.T) {
+	sourcePath := t.TempDir()
+	if core.HasCatalog(sourcePath) {
+		t.Errorf("HasCatalog(%q) = true, expected false", sourcePath)
+	}
+	_, err := core.LoadCatalog(sourcePath)
+	if err == nil {
+		t.Errorf("LoadCatalog without %s expected an error", core.CatalogFileName)
+	}
+}
+
+func TestFindCatalogTemplate(t *testing.T) {
+	catalog := &entity.Catalog{Templates: []entity.CatalogTemplate{
+		{Name: "go-service", Path: "templates/go-service"},
+		{Name: "go-lib", Path: "templates/go-lib"},
+	}}
+
+	testCases := []struct {
+		name          string
+		expectedPath  string
+		expectedFound bool
+	}{
+		{name: "go-service", expectedPath: "templates/go-service", expectedFound: true},
+		{name: "go-lib", expectedPath: "templates/go-lib", expectedFound: true},
+		{name: "go-cli", expectedFound: false},
+		{name: "", expectedFound: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, found := core.FindCatalogTemplate(catalog, tc.name)
+			if found != tc.expectedFound || actual.Path != tc.expectedPath {
+				t.Errorf("FindCatalogTemplate(%q) = %q, %t, expected %q, %t", tc.name, actual.Path, found, tc.expectedPath, tc.expectedFound)
+			}
+		})
+	}
+}
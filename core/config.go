@@ -0,0 +1,229 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadTemplateLayer parses the template configuration file of a template root
+func LoadTemplateLayer(rootPath string, configPath string) (entity.TemplateLayer, error) {
+	layer := entity.TemplateLayer{
+		RootPath:   rootPath,
+		ConfigPath: configPath,
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+
+	err := v.ReadInConfig()
+	if err != nil {
+		return layer, err
+	}
+
+	err = v.Unmarshal(&layer.Prompt)
+	if err != nil {
+		return layer, err
+	}
+
+	// conditional_paths declared as a map is read from the file itself (see mapConditionalPaths)
+	conditionalPaths, isMap, err := mapConditionalPaths(configPath)
+	if err != nil {
+		return layer, err
+	}
+	if isMap {
+		v.Set("conditional_paths", nil)
+	}
+
+	err = v.Unmarshal(&layer.Config)
+	if err != nil {
+		return layer, err
+	}
+	if isMap {
+		layer.Config.ConditionalPaths = conditionalPaths
+	}
+
+	return layer, nil
+}
+
+// mapConditionalPaths parses conditional_paths when declared as a map from glob to expression,
+// keeping the globs as written (viper lower-cases map keys and splits them on dots)
+func mapConditionalPaths(configPath string) ([]entity.ConditionalPath, bool, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	config := struct {
+		ConditionalPaths yaml.Node `yaml:"conditional_paths"`
+	}{}
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, false, err
+	}
+	if config.ConditionalPaths.Kind != yaml.MappingNode {
+		return nil, false, nil
+	}
+
+	conditionalPaths := []entity.ConditionalPath{}
+	content := config.ConditionalPaths.Content
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i+1].Kind != yaml.ScalarNode {
+			return nil, true, fmt.Errorf("conditional path %s must map to an expression", content[i].Value)
+		}
+		conditionalPaths = append(conditionalPaths, entity.ConditionalPath{Path: content[i].Value, When: content[i+1].Value})
+	}
+	return conditionalPaths, true, nil
+}
+
+// BaseTemplateFetcher clones a base template repository, returning its local path along with
+// a cleanup function removing it
+type BaseTemplateFetcher func(location string) (string, func(), error)
+
+// LoadTemplateLayers follows the extends chain of a template and returns every layer (base templates
+// first) along with a cleanup function for the fetched base templates (already called on errors)
+func LoadTemplateLayers(rootPath string, configPath string, abbreviations map[string]string, fetch BaseTemplateFetcher) ([]entity.TemplateLayer, func(), error) {
+	layers := []entity.TemplateLayer{}
+	cleanups := []func(){}
+	cleanup := func() {
+		for _, cleanupSource := range cleanups {
+			cleanupSource()
+		}
+	}
+
+	rootLocation, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	visited := map[string]bool{rootLocation: true}
+	for {
+		layer := entity.TemplateLayer{RootPath: rootPath, ConfigPath: configPath}
+		hasConfig, _ := PathExists(configPath)
+		if hasConfig {
+			layer, err = LoadTemplateLayer(rootPath, configPath)
+			if err != nil {
+				cleanup()
+				return nil, func() {}, fmt.Errorf("loading %s: %w", configPath, err)
+			}
+		} else if len(layers) == 0 {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("config file %s not found", configPath)
+		}
+
+		layers = append([]entity.TemplateLayer{layer}, layers...)
+		if layer.Config.Extends == "" {
+			return layers, cleanup, nil
+		}
+
+		// Base templates are resolved relative to the template extending them
+		location := ResolveSource(layer.Config.Extends, abbreviations)
+		if !IsRepositoryURL(location) {
+			if !filepath.IsAbs(location) {
+				location = filepath.Join(rootPath, location)
+			}
+			location, err = filepath.Abs(location)
+			if err != nil {
+				cleanup()
+				return nil, func() {}, err
+			}
+		}
+
+		if visited[location] {
+			cleanup()
+			return nil, func() {}, fmt.Errorf("template %s extends %s: the extends chain contains a cycle", rootPath, location)
+		}
+		visited[location] = true
+
+		basePath := location
+		if IsRepositoryURL(location) {
+			var cleanupSource func()
+			basePath, cleanupSource, err = fetch(location)
+			if err != nil {
+				cleanup()
+				return nil, func() {}, fmt.Errorf("template %s extends %s: %w", rootPath, location, err)
+			}
+			cleanups = append(cleanups, cleanupSource)
+		}
+
+		rootPath = basePath
+		configPath = filepath.Join(basePath, TemplateConfigFileName)
+	}
+}
+
+// MergePrompts merges the prompts of every layer, later layers overriding/adding prompts by key
+func MergePrompts(layers []entity.TemplateLayer) entity.Prompt {
+	merged := entity.Prompt{Items: make(map[string]entity.PromptItem)}
+	for _, layer := range layers {
+		for key, item := range layer.Prompt.Items {
+			merged.Items[key] = item
+		}
+	}
+	return merged
+}
+
+// MergeTemplateConfigs merges the settings of every layer into the ones used for generation
+// (metadata comes from the last layer, lists are accumulated)
+func MergeTemplateConfigs(layers []entity.TemplateLayer) entity.TemplateConfig {
+	merged := entity.TemplateConfig{}
+	for _, layer := range layers {
+		merged.Template = layer.Config.Template
+		merged.CopyWithoutRender = append(merged.CopyWithoutRender, layer.Config.CopyWithoutRender...)
+		merged.ForceRender = append(merged.ForceRender, layer.Config.ForceRender...)
+		merged.ConditionalPaths = mergeConditionalPaths(merged.ConditionalPaths, layer.Config.ConditionalPaths)
+		merged.SkipIfExists = append(merged.SkipIfExists, layer.Config.SkipIfExists...)
+		merged.FileModes = append(merged.FileModes, layer.Config.FileModes...)
+		merged.Format = append(merged.Format, layer.Config.Format...)
+		if layer.Config.StrictUndefined != nil {
+			merged.StrictUndefined = layer.Config.StrictUndefined
+		}
+		if layer.Config.Manifest.Path != "" {
+			merged.Manifest.Path = layer.Config.Manifest.Path
+		}
+		merged.Manifest.Disabled = merged.Manifest.Disabled || layer.Config.Manifest.Disabled
+		if layer.Config.Umask != "" {
+			merged.Umask = layer.Config.Umask
+		}
+	}
+	return merged
+}
+
+// mergeConditionalPaths adds the conditional paths of a layer, replacing the condition of the
+// paths already declared by a base template
+func mergeConditionalPaths(base []entity.ConditionalPath, layer []entity.ConditionalPath) []entity.ConditionalPath {
+	merged := base
+	for _, conditionalPath := range layer {
+		i := slices.IndexFunc(merged, func(declared entity.ConditionalPath) bool {
+			return declared.Path == conditionalPath.Path
+		})
+		if i == -1 {
+			merged = append(merged, conditionalPath)
+			continue
+		}
+		merged[i] = conditionalPath
+	}
+	return merged
+}
+
+// CheckScaffoldVersion verifies every layer of the template supports the scaffold version running
+func CheckScaffoldVersion(layers []entity.TemplateLayer, version string) error {
+	for _, layer := range layers {
+		requirement := layer.Config.Template.RequiresScaffold
+		if requirement == "" {
+			continue
+		}
+
+		satisfied, err := CheckVersionConstraint(version, requirement)
+		if err != nil {
+			return fmt.Errorf("template %s: %w", layer.RootPath, err)
+		}
+		if !satisfied {
+			return fmt.Errorf("template %s requires scaffold %s (running %s)", layer.RootPath, requirement, version)
+		}
+	}
+	return nil
+}
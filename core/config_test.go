@@ -0,0 +1,245 @@
+package core_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestLoadTemplateLayers(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"service/scaffold.yaml": "extends: ../base\nprompt:\n  name:\n    default: svc\n",
+		"base/scaffold.yaml":    "extends: " + filepath.Join(root, "common") + "\nprompt:\n  owner:\n    default: me\n",
+		"common/README.md":      "# common\n",
+		"remote/scaffold.yaml":  "extends: gh:org/base\n",
+		"fetched/scaffold.yaml": "prompt:\n  license:\n    default: MIT\n",
+		"cycle-a/scaffold.yaml": "extends: ../cycle-b\n",
+		"cycle-b/scaffold.yaml": "extends: ../cycle-a\n",
+		"self/scaffold.yaml":    "extends: .\n",
+		"broken/scaffold.yaml":  "extends: [\n",
+	})
+
+	fetched := []string{}
+	cleaned := 0
+	fetch := func(location string) (string, func(), error) {
+		fetched = append(fetched, location)
+		if location != "https://github.com/org/base.git" {
+			return "", nil, errors.New("repository not found")
+		}
+		return filepath.Join(root, "fetched"), func() { cleaned++ }, nil
+	}
+	abbreviations := core.SourceAbbreviations(nil)
+
+	testCases := []struct {
+		name          string
+		template      string
+		expectedRoots []string
+		expectErr     string
+	}{
+		{
+			name:     "local chain",
+			template: "service",
+			// Base templates without configuration are layers too
+			expectedRoots: []string{"common", "base", "service"},
+		},
+		{
+			name:          "fetched base",
+			template:      "remote",
+			expectedRoots: []string{"fetched", "remote"},
+		},
+		{name: "cycle", template: "cycle-a", expectErr: "cycle"},
+		{name: "extends itself", template: "self", expectErr: "cycle"},
+		{name: "missing config", template: "missing", expectErr: "not found"},
+		{name: "invalid config", template: "broken", expectErr: "loading"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rootPath := filepath.Join(root, tc.template)
+			layers, cleanup, err := core.LoadTemplateLayers(rootPath, filepath.Join(rootPath, core.TemplateConfigFileName), abbreviations, fetch)
+			if tc.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Errorf("LoadTemplateLayers(%q) error = %v, expected %q", tc.template, err, tc.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTemplateLayers(%q) failed: %v", tc.template, err)
+			}
+			cleanup()
+
+			roots := []string{}
+			for _, layer := range layers {
+				relPath, err := filepath.Rel(root, layer.RootPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				roots = append(roots, relPath)
+			}
+			if !reflect.DeepEqual(roots, tc.expectedRoots) {
+				t.Errorf("LoadTemplateLayers(%q) roots = %v, expected %v", tc.template, roots, tc.expectedRoots)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(fetched, []string{"https://github.com/org/base.git"}) || cleaned != 1 {
+		t.Errorf("fetched %v (%d cleaned), expected the gh:org/base repository once", fetched, cleaned)
+	}
+}
+
+func TestLoadTemplateLayerConditionalPaths(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    string
+		expected  []entity.ConditionalPath
+		expectErr bool
+	}{
+		{
+			name: "map",
+			config: "conditional_paths:\n" +
+				"  \"*/migrations\": \"scaffold.database != 'none'\"\n" +
+				"  \"*/Dockerfile\": scaffold.use_docker\n" +
+				"  \"*/docs/*.md\": scaffold.docs\n",
+			expected: []entity.ConditionalPath{
+				{Path: "*/migrations", When: "scaffold.database != 'none'"},
+				{Path: "*/Dockerfile", When: "scaffold.use_docker"},
+				{Path: "*/docs/*.md", When: "scaffold.docs"},
+			},
+		},
+		{
+			name: "list",
+			config: "conditional_paths:\n" +
+				"  - path: \"*/Dockerfile\"\n" +
+				"    when: scaffold.use_docker\n",
+			expected: []entity.ConditionalPath{{Path: "*/Dockerfile", When: "scaffold.use_docker"}},
+		},
+		{
+			name:     "none",
+			config:   "skip_if_exists: [\"**/.env\"]\n",
+			expected: nil,
+		},
+		{
+			name:      "map to a non expression",
+			config:    "conditional_paths:\n  \"*/docs\": [scaffold.docs]\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rootPath := t.TempDir()
+			writeTestFiles(t, rootPath, map[string]string{core.TemplateConfigFileName: tc.config})
+
+			layer, err := core.LoadTemplateLayer(rootPath, filepath.Join(rootPath, core.TemplateConfigFileName))
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("LoadTemplateLayer expected an error, got %v", layer.Config.ConditionalPaths)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTemplateLayer failed: %v", err)
+			}
+			if !reflect.DeepEqual(layer.Config.ConditionalPaths, tc.expected) {
+				t.Errorf("ConditionalPaths = %v, expected %v", layer.Config.ConditionalPaths, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLoadTemplateLayersFetchFailure(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"service/scaffold.yaml": "extends: https://example.com/base.git\n",
+	})
+
+	fetch := func(location string) (string, func(), error) {
+		return "", nil, errors.New("repository not found")
+	}
+
+	rootPath := filepath.Join(root, "service")
+	_, _, err := core.LoadTemplateLayers(rootPath, filepath.Join(rootPath, core.TemplateConfigFileName), nil, fetch)
+	if err == nil || !strings.Contains(err.Error(), "repository not found") {
+		t.Errorf("LoadTemplateLayers error = %v, expected the fetch error", err)
+	}
+}
+
+func TestMergePrompts(t *testing.T) {
+	layers := []entity.TemplateLayer{
+		{Prompt: entity.Prompt{Items: map[string]entity.PromptItem{
+			"name":  {OrderID: 1, DefaultValue: "base"},
+			"owner": {OrderID: 2, DefaultValue: "me"},
+		}}},
+		{Prompt: entity.Prompt{}},
+		{Prompt: entity.Prompt{Items: map[string]entity.PromptItem{
+			"name":    {OrderID: 1, DefaultValue: "service"},
+			"license": {OrderID: 3, DefaultValue: "MIT"},
+		}}},
+	}
+
+	expected := entity.Prompt{Items: map[string]entity.PromptItem{
+		"name":    {OrderID: 1, DefaultValue: "service"},
+		"owner":   {OrderID: 2, DefaultValue: "me"},
+		"license": {OrderID: 3, DefaultValue: "MIT"},
+	}}
+
+	actual := core.MergePrompts(layers)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("MergePrompts = %+v, expected %+v", actual, expected)
+	}
+}
+
+func TestMergeTemplateConfigs(t *testing.T) {
+	strict := true
+	lenient := false
+
+	layers := []entity.TemplateLayer{
+		{Config: entity.TemplateConfig{
+			Template:          entity.TemplateMetadata{Name: "base"},
+			CopyWithoutRender: []string{"*/charts"},
+			ConditionalPaths: []entity.ConditionalPath{
+				{Path: "*/docker", When: "scaffold.docker"},
+				{Path: "*/migrations", When: "scaffold.database"},
+			},
+			SkipIfExists:    []string{"**/.env"},
+			StrictUndefined: &lenient,
+			Manifest:        entity.ManifestConfig{Path: ".meta/answers.yaml"},
+			Umask:           "022",
+		}},
+		{Config: entity.TemplateConfig{
+			Template:          entity.TemplateMetadata{Name: "service"},
+			CopyWithoutRender: []string{"**/*.tmpl"},
+			ConditionalPaths:  []entity.ConditionalPath{{Path: "*/docker", When: "scaffold.use_docker"}, {Path: "*/ci", When: "scaffold.ci"}},
+			Format:            []string{"go"},
+			StrictUndefined:   &strict,
+			Manifest:          entity.ManifestConfig{Disabled: true},
+		}},
+	}
+
+	expected := entity.TemplateConfig{
+		Template:          entity.TemplateMetadata{Name: "service"},
+		CopyWithoutRender: []string{"*/charts", "**/*.tmpl"},
+		// Conditions of base template paths are replaced
+		ConditionalPaths: []entity.ConditionalPath{
+			{Path: "*/docker", When: "scaffold.use_docker"},
+			{Path: "*/migrations", When: "scaffold.database"},
+			{Path: "*/ci", When: "scaffold.ci"},
+		},
+		SkipIfExists:    []string{"**/.env"},
+		Format:          []string{"go"},
+		StrictUndefined: &strict,
+		Manifest:        entity.ManifestConfig{Path: ".meta/answers.yaml", Disabled: true},
+		Umask:           "022",
+	}
+
+	actual := core.MergeTemplateConfigs(layers)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("MergeTemplateConfigs = %+v, expected %+v", actual, expected)
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/copito/goscaffold/entity"
+)
+
+// Actions available for a generated file that already exists in the output folder
+const (
+	ConflictOverwrite = "overwrite"
+	ConflictKeep      = "keep"
+	ConflictShowDiff  = "show diff"
+	ConflictWriteNew  = "write as .new"
+)
+
+// ConflictActions are the choices offered when asking how to handle an existing file
+var ConflictActions = []string{ConflictOverwrite, ConflictKeep, ConflictShowDiff, ConflictWriteNew}
+
+// NewFileSuffix is appended to generated files written next to the existing ones
+const NewFileSuffix = ".new"
+
+// ConflictAction returns the action for a generated path that already exists, or "" when
+// the user has to choose (skip_if_exists paths are always kept, even with --overwrite)
+func ConflictAction(skipIfExists []string, renderedPath string, overwrite bool, skipExisting bool) (string, error) {
+	matched, err := MatchAnyGlobOrParent(skipIfExists, renderedPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid skip_if_exists pattern: %w", err)
+	}
+
+	switch {
+	case matched || skipExisting:
+		return ConflictKeep, nil
+	case overwrite:
+		return ConflictOverwrite, nil
+	}
+	return "", nil
+}
+
+// ExistingOutput returns whether the output path of a plan entry exists, failing when the existing
+// path can not be merged with it (folders are merged, files and symlinks replace files and symlinks)
+func ExistingOutput(entry entity.PlanEntry, outputPath string) (bool, error) {
+	info, err := os.Lstat(outputPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if entry.Info.IsDir() != info.IsDir() {
+		if info.IsDir() {
+			return true, fmt.Errorf("%s already exists as a folder", outputPath)
+		}
+		return true, fmt.Errorf("%s already exists and is not a folder", outputPath)
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestConflictAction(t *testing.T) {
+	skipIfExists := []string{"**/.env", "*/CHANGELOG.md"}
+
+	testCases := []struct {
+		name         string
+		renderedPath string
+		overwrite    bool
+		skipExisting bool
+		expected     string
+	}{
+		{name: "ask by default", renderedPath: "svc/main.go", expected: ""},
+		{name: "overwrite", renderedPath: "svc/main.go", overwrite: true, expected: core.ConflictOverwrite},
+		{name: "skip existing", renderedPath: "svc/main.go", skipExisting: true, expected: core.ConflictKeep},
+		{name: "skip_if_exists wins over overwrite", renderedPath: "svc/CHANGELOG.md", overwrite: true, expected: core.ConflictKeep},
+		{name: "skip_if_exists nested", renderedPath: "svc/config/.env", expected: core.ConflictKeep},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.ConflictAction(skipIfExists, tc.renderedPath, tc.overwrite, tc.skipExisting)
+			if err != nil {
+				t.Fatalf("ConflictAction(%q) failed: %v", tc.renderedPath, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("ConflictAction(%q) = %q, expected %q", tc.renderedPath, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExistingOutput(t *testing.T) {
+	root := t.TempDir()
+	err := os.Mkdir(filepath.Join(root, "folder"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "file"), nil, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	folderInfo, _ := os.Lstat(filepath.Join(root, "folder"))
+	fileInfo, _ := os.Lstat(filepath.Join(root, "file"))
+
+	testCases := []struct {
+		name      string
+		info      os.FileInfo
+		output    string
+		expected  bool
+		expectErr bool
+	}{
+		{name: "missing", info: fileInfo, output: "missing", expected: false},
+		{name: "existing file", info: fileInfo, output: "file", expected: true},
+		{name: "existing folder", info: folderInfo, output: "folder", expected: true},
+		{name: "file over folder", info: fileInfo, output: "folder", expected: true, expectErr: true},
+		{name: "folder over file", info: folderInfo, output: "file", expected: true, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.ExistingOutput(entity.PlanEntry{Info: tc.info}, filepath.Join(root, tc.output))
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("ExistingOutput(%q) error = %v, expected error %t", tc.output, err, tc.expectErr)
+			}
+			if actual != tc.expected {
+				t.Errorf("ExistingOutput(%q) = %t, expected %t", tc.output, actual, tc.expected)
+			}
+		})
+	}
+}
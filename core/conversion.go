@@ -1,6 +1,9 @@
 package core
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 func InterfaceSliceToStringSlice(data []interface{}) []string {
 	strings := make([]string, len(data))
@@ -15,3 +18,26 @@ func InterfaceSliceToStringSlice(data []interface{}) []string {
 	}
 	return strings
 }
+
+// IsScalarSlice returns whether every element of data is a single value (no list or mapping)
+func IsScalarSlice(data []interface{}) bool {
+	for _, v := range data {
+		switch v.(type) {
+		case []interface{}, map[string]interface{}, map[interface{}]interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+// SplitList splits a comma separated list of values (ignoring empty ones)
+func SplitList(value string) []interface{} {
+	list := []interface{}{}
+	for _, element := range strings.Split(value, ",") {
+		element = strings.TrimSpace(element)
+		if element != "" {
+			list = append(list, element)
+		}
+	}
+	return list
+}
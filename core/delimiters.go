@@ -0,0 +1,76 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/copito/goscaffold/entity"
+	"github.com/kluctl/go-jinja2"
+)
+
+// MergeDelimiters overrides the delimiters of base with the ones set in override
+func MergeDelimiters(base entity.Delimiters, override entity.Delimiters) entity.Delimiters {
+	merged := base
+	for _, field := range []struct {
+		value  string
+		target *string
+	}{
+		{override.BlockStart, &merged.BlockStart},
+		{override.BlockEnd, &merged.BlockEnd},
+		{override.VariableStart, &merged.VariableStart},
+		{override.VariableEnd, &merged.VariableEnd},
+		{override.CommentStart, &merged.CommentStart},
+		{override.CommentEnd, &merged.CommentEnd},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	return merged
+}
+
+// FileDelimiters returns the delimiters used for a template path of a layer
+// (template delimiters overridden by every matching delimiter override)
+func FileDelimiters(config entity.TemplateConfig, templatePath string) (entity.Delimiters, error) {
+	delimiters := config.Delimiters
+	for _, override := range config.DelimiterOverrides {
+		matched, err := MatchAnyGlobOrParent([]string{override.Path}, templatePath)
+		if err != nil {
+			return delimiters, err
+		}
+		if matched {
+			delimiters = MergeDelimiters(delimiters, override.Delimiters)
+		}
+	}
+	return delimiters, nil
+}
+
+// DelimiterOptions configures the Jinja environment of a render with the delimiters
+func DelimiterOptions(delimiters entity.Delimiters) []jinja2.Jinja2Opt {
+	return []jinja2.Jinja2Opt{
+		jinja2.WithGlobal("_scaffold_delimiters", map[string]string{
+			"block_start_string":    delimiters.BlockStart,
+			"block_end_string":      delimiters.BlockEnd,
+			"variable_start_string": delimiters.VariableStart,
+			"variable_end_string":   delimiters.VariableEnd,
+			"comment_start_string":  delimiters.CommentStart,
+			"comment_end_string":    delimiters.CommentEnd,
+		}),
+	}
+}
+
+// ForceRenderMarker returns an (empty) comment prepended to templates that do not contain
+// any "{": the jinja renderer returns those untouched, even when using custom delimiters
+func ForceRenderMarker(delimiters entity.Delimiters, template string) string {
+	if delimiters == (entity.Delimiters{}) || strings.ContainsRune(template, '{') {
+		return ""
+	}
+
+	commentStart, commentEnd := "{#", "#}"
+	if delimiters.CommentStart != "" {
+		commentStart = delimiters.CommentStart
+	}
+	if delimiters.CommentEnd != "" {
+		commentEnd = delimiters.CommentEnd
+	}
+	return commentStart + "{" + commentEnd
+}
@@ -0,0 +1,75 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestRenderStringDelimiters(t *testing.T) {
+	engine, err := core.NewJinjaEngine(map[string]any{"name": "svc"}, core.EngineOptions{})
+	if err != nil {
+		t.Fatalf("NewJinjaEngine failed: %v", err)
+	}
+	defer engine.Close()
+
+	squareDelimiters := entity.Delimiters{
+		BlockStart:    "[%",
+		BlockEnd:      "%]",
+		VariableStart: "[[",
+		VariableEnd:   "]]",
+		CommentStart:  "[#",
+		CommentEnd:    "#]",
+	}
+
+	testCases := []struct {
+		name       string
+		template   string
+		delimiters entity.Delimiters
+		expected   string
+	}{
+		{name: "default", template: "name: {{ scaffold.name }}", expected: "name: svc"},
+		{name: "custom without braces", template: "name: [[ scaffold.name ]][# note #]", delimiters: squareDelimiters, expected: "name: svc"},
+		{name: "custom with braces", template: "[% if true %]{{ .Values.name }}: [[ scaffold.name | upper ]][% endif %]", delimiters: squareDelimiters, expected: "{{ .Values.name }}: SVC"},
+		{name: "variables only", template: "{% raw %}x{% endraw %} <<scaffold.name>>", delimiters: entity.Delimiters{VariableStart: "<<", VariableEnd: ">>"}, expected: "x svc"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := engine.RenderString(tc.template, tc.delimiters)
+			if err != nil {
+				t.Fatalf("RenderString(%q) failed: %v", tc.template, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("RenderString(%q) = %q, expected %q", tc.template, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFileDelimiters(t *testing.T) {
+	config := entity.TemplateConfig{
+		Delimiters: entity.Delimiters{VariableStart: "[[", VariableEnd: "]]"},
+		DelimiterOverrides: []entity.DelimiterOverride{
+			{Path: "*/charts", Delimiters: entity.Delimiters{BlockStart: "[%", BlockEnd: "%]"}},
+		},
+	}
+
+	delimiters, err := core.FileDelimiters(config, "project/charts/templates/deployment.yaml")
+	if err != nil {
+		t.Fatalf("FileDelimiters failed: %v", err)
+	}
+	expected := entity.Delimiters{VariableStart: "[[", VariableEnd: "]]", BlockStart: "[%", BlockEnd: "%]"}
+	if delimiters != expected {
+		t.Errorf("FileDelimiters = %+v, expected %+v", delimiters, expected)
+	}
+
+	delimiters, err = core.FileDelimiters(config, "project/main.go")
+	if err != nil {
+		t.Fatalf("FileDelimiters failed: %v", err)
+	}
+	if delimiters != config.Delimiters {
+		t.Errorf("FileDelimiters = %+v, expected %+v", delimiters, config.Delimiters)
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContextLines is the number of unchanged lines shown around every change
+const diffContextLines = 3
+
+// diffLine is one line of a line diff ("-" removed, "+" added, " " unchanged)
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// LineDiff returns the unified diff between the existing and the generated content of
+// a file (empty when both are the same)
+func LineDiff(name string, existing string, generated string) string {
+	if existing == generated {
+		return ""
+	}
+
+	lines := diffLines(existing, generated)
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "--- %s (existing)\n+++ %s (generated)\n", name, name)
+
+	oldLine, newLine := 1, 1
+	for start := 0; start < len(lines); {
+		// Next change, along with its context lines
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		// Unchanged lines before the hunk belong to both files
+		hunkStart := max(start, first-diffContextLines)
+		oldLine, newLine = oldLine+hunkStart-start, newLine+hunkStart-start
+
+		// The hunk goes on while changes are closer than twice the context
+		hunkEnd, unchanged := first, 0
+		for i := first; i < len(lines) && unchanged <= 2*diffContextLines; i++ {
+			if lines[i].op == ' ' {
+				unchanged++
+				continue
+			}
+			unchanged = 0
+			hunkEnd = i + 1
+		}
+		hunkEnd = min(len(lines), hunkEnd+diffContextLines)
+
+		oldCount, newCount := 0, 0
+		for _, line := range lines[hunkStart:hunkEnd] {
+			if line.op != '+' {
+				oldCount++
+			}
+			if line.op != '-' {
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(&builder, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, line := range lines[hunkStart:hunkEnd] {
+			fmt.Fprintf(&builder, "%c%s\n", line.op, line.text)
+		}
+
+		oldLine, newLine = oldLine+oldCount, newLine+newCount
+		start = hunkEnd
+	}
+	return builder.String()
+}
+
+// diffLines compares two texts line by line
+func diffLines(existing string, generated string) []diffLine {
+	dmp := diffmatchpatch.New()
+	existingChars, generatedChars, lineArray := dmp.DiffLinesToChars(existing, generated)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(existingChars, generatedChars, false), lineArray)
+
+	lines := []diffLine{}
+	for _, diff := range diffs {
+		op := byte(' ')
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, text := range strings.SplitAfter(diff.Text, "\n") {
+			if text == "" {
+				continue
+			}
+			lines = append(lines, diffLine{op: op, text: strings.TrimSuffix(text, "\n")})
+		}
+	}
+	return lines
+}
@@ -0,0 +1,58 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestLineDiff(t *testing.T) {
+	testCases := []struct {
+		name      string
+		existing  string
+		generated string
+		expected  string
+	}{
+		{
+			name:      "same content",
+			existing:  "a\nb\n",
+			generated: "a\nb\n",
+			expected:  "",
+		},
+		{
+			name:      "changed line",
+			existing:  "a\nb\nc\n",
+			generated: "a\nB\nc\n",
+			expected:  "--- f (existing)\n+++ f (generated)\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:      "new file content",
+			existing:  "",
+			generated: "a\n",
+			expected:  "--- f (existing)\n+++ f (generated)\n@@ -1,0 +1,1 @@\n+a\n",
+		},
+		{
+			name:      "distant changes are separate hunks",
+			existing:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			generated: "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			expected: "--- f (existing)\n+++ f (generated)\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name:      "close changes share a hunk",
+			existing:  "1\n2\n3\n4\n5\n6\n7\n8\n",
+			generated: "one\n2\n3\n4\n5\n6\n7\neight\n",
+			expected:  "--- f (existing)\n+++ f (generated)\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := core.LineDiff("f", tc.existing, tc.generated)
+			if actual != tc.expected {
+				t.Errorf("LineDiff() =\n%s\nexpected\n%s", actual, tc.expected)
+			}
+		})
+	}
+}
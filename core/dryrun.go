@@ -0,0 +1,214 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/afero"
+)
+
+// Formats of the dry-run report (--dry-run defaults to the tree)
+const (
+	DryRunTree = "tree"
+	DryRunJSON = "json"
+)
+
+// Types and statuses of the paths a dry-run would generate
+const (
+	DryRunFolder  = "folder"
+	DryRunFile    = "file"
+	DryRunSymlink = "symlink"
+
+	DryRunCreate    = "create"
+	DryRunChange    = "change"
+	DryRunUnchanged = "unchanged"
+	DryRunKeep      = "keep"
+)
+
+// CheckDryRunFormat verifies the format requested with --dry-run ("" when not a dry-run)
+func CheckDryRunFormat(format string) error {
+	switch format {
+	case "", DryRunTree, DryRunJSON:
+		return nil
+	}
+	return fmt.Errorf("unknown dry-run format %q (expected %s or %s)", format, DryRunTree, DryRunJSON)
+}
+
+// DryRunEntries compares the plan generated to fs (the in-memory output of a dry-run), along with
+// the extra files written outside the plan (e.g. the manifest), to the output folder on disk
+func DryRunEntries(fs afero.Fs, plan map[string]entity.PlanEntry, outputBasePath string, extraFiles []string) ([]entity.DryRunEntry, error) {
+	entries := []entity.DryRunEntry{}
+	for _, planEntry := range SortedPlan(plan) {
+		fullPath := filepath.Join(outputBasePath, filepath.FromSlash(planEntry.RenderedPath))
+		entry := entity.DryRunEntry{Path: planEntry.RenderedPath}
+
+		switch mode := planEntry.Info.Mode(); {
+		case planEntry.Kept:
+			entry.Type, entry.Status = DryRunFile, DryRunKeep
+			if mode&os.ModeSymlink != 0 {
+				entry.Type = DryRunSymlink
+			}
+			if info, err := os.Lstat(fullPath); err == nil {
+				entry.Size = info.Size()
+			}
+
+		case mode.IsDir():
+			entry.Type, entry.Status = DryRunFolder, DryRunUnchanged
+			if _, err := os.Lstat(fullPath); os.IsNotExist(err) {
+				entry.Status = DryRunCreate
+			}
+
+		case mode&os.ModeSymlink != 0:
+			entry.Type, entry.Target = DryRunSymlink, planEntry.LinkTarget
+			target, err := os.Readlink(fullPath)
+			switch {
+			case err != nil:
+				entry.Status = DryRunCreate
+			case target == planEntry.LinkTarget:
+				entry.Status = DryRunUnchanged
+			default:
+				entry.Status = DryRunChange
+			}
+
+		default:
+			fileEntry, err := dryRunFile(fs, planEntry.RenderedPath, fullPath)
+			if err != nil {
+				return nil, err
+			}
+			entry = fileEntry
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, renderedPath := range extraFiles {
+		entry, err := dryRunFile(fs, renderedPath, filepath.Join(outputBasePath, filepath.FromSlash(renderedPath)))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// dryRunFile compares a file generated to fs with the one on disk
+func dryRunFile(fs afero.Fs, renderedPath string, fullPath string) (entity.DryRunEntry, error) {
+	entry := entity.DryRunEntry{Path: renderedPath, Type: DryRunFile}
+	generated, err := afero.ReadFile(fs, fullPath)
+	if err != nil {
+		return entry, err
+	}
+	entry.Size = int64(len(generated))
+
+	existing, err := os.ReadFile(fullPath)
+	switch {
+	case os.IsNotExist(err):
+		entry.Status = DryRunCreate
+	case err != nil:
+		return entry, err
+	case bytes.Equal(existing, generated):
+		entry.Status = DryRunUnchanged
+	default:
+		entry.Status = DryRunChange
+	}
+	return entry, nil
+}
+
+// dryRunNode is a path of the dry-run tree (folders only implied by their content have no entry)
+type dryRunNode struct {
+	name     string
+	entry    *entity.DryRunEntry
+	children []*dryRunNode
+}
+
+// FormatDryRunTree renders the paths of a dry-run as a tree rooted at the output folder
+func FormatDryRunTree(outputBasePath string, entries []entity.DryRunEntry) string {
+	root := &dryRunNode{}
+	counts := map[string]int{}
+	for i, entry := range entries {
+		node := root
+		for _, name := range strings.Split(entry.Path, "/") {
+			var child *dryRunNode
+			for _, existing := range node.children {
+				if existing.name == name {
+					child = existing
+				}
+			}
+			if child == nil {
+				child = &dryRunNode{name: name}
+				node.children = append(node.children, child)
+			}
+			node = child
+		}
+		node.entry = &entries[i]
+
+		if entry.Type != DryRunFolder {
+			counts[entry.Status]++
+		}
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(outputBasePath + "\n")
+	writeDryRunTree(&builder, root, "")
+	fmt.Fprintf(&builder, "\n%d to create, %d to change, %d unchanged, %d kept\n", counts[DryRunCreate], counts[DryRunChange], counts[DryRunUnchanged], counts[DryRunKeep])
+	return builder.String()
+}
+
+// writeDryRunTree writes the children of a node of the dry-run tree (one line each)
+func writeDryRunTree(builder *strings.Builder, node *dryRunNode, prefix string) {
+	for i, child := range node.children {
+		connector, childPrefix := "├── ", "│   "
+		if i == len(node.children)-1 {
+			connector, childPrefix = "└── ", "    "
+		}
+
+		line := child.name + "/"
+		if entry := child.entry; entry != nil {
+			switch entry.Type {
+			case DryRunFolder:
+				line = fmt.Sprintf("%s/ (%s)", child.name, entry.Status)
+			case DryRunSymlink:
+				line = fmt.Sprintf("%s -> %s (%s)", child.name, entry.Target, entry.Status)
+			default:
+				line = fmt.Sprintf("%s (%s, %s)", child.name, entry.Status, FormatSize(entry.Size))
+			}
+		}
+
+		builder.WriteString(prefix + connector + line + "\n")
+		writeDryRunTree(builder, child, prefix+childPrefix)
+	}
+}
+
+// FormatDryRunJSON renders the paths of a dry-run as JSON (for tooling)
+func FormatDryRunJSON(outputBasePath string, entries []entity.DryRunEntry) (string, error) {
+	data, err := json.MarshalIndent(entity.DryRun{Output: outputBasePath, Entries: entries}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// FormatSize returns a human readable file size (e.g. "512 B", "1.5 KiB")
+func FormatSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size)
+	for _, unit := range []string{"KiB", "MiB", "GiB"} {
+		value /= 1024
+		if value < 1024 || unit == "GiB" {
+			return fmt.Sprintf("%.1f %s", value, unit)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/afero"
+)
+
+func TestDryRunEntries(t *testing.T) {
+	outputBasePath := t.TempDir()
+	writeTestFiles(t, outputBasePath, map[string]string{
+		"svc/main.go":   "package main\n",
+		"svc/README.md": "# old\n",
+		"svc/.env":      "A=1\n",
+	})
+	folderInfo, _ := os.Lstat(filepath.Join(outputBasePath, "svc"))
+	fileInfo, _ := os.Lstat(filepath.Join(outputBasePath, "svc", "main.go"))
+
+	fs := afero.NewMemMapFs()
+	generated := map[string]string{
+		"svc/main.go":                "package main\n",
+		"svc/README.md":              "# svc\n",
+		"svc/cmd/worker.go":          "package cmd\n",
+		"svc/.scaffold/answers.yaml": "answers: {}\n",
+	}
+	for renderedPath, content := range generated {
+		err := afero.WriteFile(fs, filepath.Join(outputBasePath, renderedPath), []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan := map[string]entity.PlanEntry{
+		"svc":               {RenderedPath: "svc", Info: folderInfo},
+		"svc/cmd":           {RenderedPath: "svc/cmd", Info: folderInfo},
+		"svc/main.go":       {RenderedPath: "svc/main.go", Info: fileInfo},
+		"svc/README.md":     {RenderedPath: "svc/README.md", Info: fileInfo},
+		"svc/cmd/worker.go": {RenderedPath: "svc/cmd/worker.go", Info: fileInfo},
+		"svc/.env":          {RenderedPath: "svc/.env", Info: fileInfo, Kept: true},
+	}
+
+	actual, err := core.DryRunEntries(fs, plan, outputBasePath, []string{"svc/.scaffold/answers.yaml"})
+	if err != nil {
+		t.Fatalf("DryRunEntries failed: %v", err)
+	}
+	expected := []entity.DryRunEntry{
+		{Path: "svc", Type: core.DryRunFolder, Status: core.DryRunUnchanged},
+		{Path: "svc/.env", Type: core.DryRunFile, Status: core.DryRunKeep, Size: 4},
+		{Path: "svc/.scaffold/answers.yaml", Type: core.DryRunFile, Status: core.DryRunCreate, Size: 12},
+		{Path: "svc/README.md", Type: core.DryRunFile, Status: core.DryRunChange, Size: 6},
+		{Path: "svc/cmd", Type: core.DryRunFolder, Status: core.DryRunCreate},
+		{Path: "svc/cmd/worker.go", Type: core.DryRunFile, Status: core.DryRunCreate, Size: 12},
+		{Path: "svc/main.go", Type: core.DryRunFile, Status: core.DryRunUnchanged, Size: 13},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("DryRunEntries = %+v, expected %+v", actual, expected)
+	}
+
+	expectedTree := "out\n" +
+		"└── svc/ (unchanged)\n" +
+		"    ├── .env (keep, 4 B)\n" +
+		"    ├── .scaffold/\n" +
+		"    │   └── answers.yaml (create, 12 B)\n" +
+		"    ├── README.md (change, 6 B)\n" +
+		"    ├── cmd/ (create)\n" +
+		"    │   └── worker.go (create, 12 B)\n" +
+		"    └── main.go (unchanged, 13 B)\n" +
+		"\n2 to create, 1 to change, 1 unchanged, 1 kept\n"
+	if tree := core.FormatDryRunTree("out", actual); tree != expectedTree {
+		t.Errorf("FormatDryRunTree =\n%s\nexpected\n%s", tree, expectedTree)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	testCases := []struct {
+		size     int64
+		expected string
+	}{
+		{size: 0, expected: "0 B"},
+		{size: 1023, expected: "1023 B"},
+		{size: 1536, expected: "1.5 KiB"},
+		{size: 5 * 1024 * 1024, expected: "5.0 MiB"},
+		{size: 3 * 1024 * 1024 * 1024 * 1024, expected: "3072.0 GiB"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			if actual := core.FormatSize(tc.size); actual != tc.expected {
+				t.Errorf("FormatSize(%d) = %q, expected %q", tc.size, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCheckDryRunFormat(t *testing.T) {
+	for _, format := range []string{"", core.DryRunTree, core.DryRunJSON} {
+		if err := core.CheckDryRunFormat(format); err != nil {
+			t.Errorf("CheckDryRunFormat(%q) failed: %v", format, err)
+		}
+	}
+	if err := core.CheckDryRunFormat("xml"); err == nil {
+		t.Errorf("CheckDryRunFormat(%q) succeeded, expected an error", "xml")
+	}
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/copito/goscaffold/entity"
+)
+
+const (
+	JinjaEngineName      = "jinja"
+	GoTemplateEngineName = "gotemplate"
+)
+
+// Engine renders templates (file names, file contents, conditions) with the chosen parameters
+// (implementations must be safe for concurrent use)
+type Engine interface {
+	// RenderString renders a template using the given delimiters (unset ones keep the engine defaults)
+	RenderString(template string, delimiters entity.Delimiters) (string, error)
+	// RenderStringWith renders a template with extra variables available next to scaffold
+	// (e.g. the item of a path loop)
+	RenderStringWith(template string, delimiters entity.Delimiters, vars map[string]any) (string, error)
+	// EvaluateCondition returns whether an expression (in the engine syntax) is truthy
+	EvaluateCondition(expression string) (bool, error)
+	// EvaluateList returns the elements of the collection an expression (in the engine syntax) evaluates to
+	EvaluateList(expression string, vars map[string]any) ([]any, error)
+	Close()
+}
+
+// EngineOptions configures a template engine
+type EngineOptions struct {
+	// Workers is the number of renders that can run concurrently
+	Workers int
+	// IncludeDirs are the folders templates can include files from (the first ones take precedence)
+	IncludeDirs []string
+	// LenientUndefined renders undefined variables empty instead of failing
+	LenientUndefined bool
+}
+
+// NewEngine creates the template engine with the given name (jinja when empty)
+// exposing the parameters as `scaffold` to templates
+func NewEngine(name string, params map[string]any, options EngineOptions) (Engine, error) {
+	switch name {
+	case "", JinjaEngineName:
+		return NewJinjaEngine(params, options)
+	case GoTemplateEngineName:
+		return NewGoTemplateEngine(params, options)
+	default:
+		return nil, fmt.Errorf("unknown template engine %s (expected %s or %s)", name, JinjaEngineName, GoTemplateEngineName)
+	}
+}
+
+// decodeList decodes the JSON list an engine rendered for a collection expression
+func decodeList(expression string, rendered string) ([]any, error) {
+	list := []any{}
+	err := json.Unmarshal([]byte(rendered), &list)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a list: %s", expression, rendered)
+	}
+	return list, nil
+}
+
+// TemplateEngineName returns the engine used by every layer of a template
+// (layers written for different engines cannot be mixed)
+func TemplateEngineName(layers []entity.TemplateLayer) (string, error) {
+	name := ""
+	for i, layer := range layers {
+		layerEngine := layer.Config.Engine
+		if layerEngine == "" {
+			layerEngine = JinjaEngineName
+		}
+
+		if i > 0 && layerEngine != name {
+			return "", fmt.Errorf("template %s uses the %s engine but its base template uses %s", layer.RootPath, layerEngine, name)
+		}
+		name = layerEngine
+	}
+	return name, nil
+}
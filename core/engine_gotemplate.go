@@ -0,0 +1,162 @@
+package core
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/copito/goscaffold/entity"
+)
+
+// GoTemplateEngine renders templates with go text/template (no embedded python runtime)
+type GoTemplateEngine struct {
+	params     map[string]any
+	data       map[string]any
+	funcs      template.FuncMap
+	includes   map[string]string
+	missingKey string
+}
+
+// goTemplateLenientFunc is appended to the actions printing values when undefined variables are
+// allowed, as text/template renders "<no value>" for missing keys of maps holding any values
+const goTemplateLenientFunc = "_scaffold_lenient"
+
+// NewGoTemplateEngine creates a text/template engine where the files of the include folders
+// are available as named templates (e.g. {{ template "license.tmpl" . }})
+func NewGoTemplateEngine(params map[string]any, options EngineOptions) (*GoTemplateEngine, error) {
+	includes, err := readIncludes(options.IncludeDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := template.FuncMap{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+		"replace": func(old string, new string, s string) string {
+			return strings.ReplaceAll(s, old, new)
+		},
+	}
+	for _, filter := range Filters {
+		funcs[filter.Name] = filter.Func
+	}
+
+	// Undefined variables render empty when not strict
+	missingKey := "error"
+	if options.LenientUndefined {
+		missingKey = "zero"
+		funcs[goTemplateLenientFunc] = lenientValue
+	}
+
+	return &GoTemplateEngine{
+		params:     params,
+		data:       map[string]any{"scaffold": params},
+		funcs:      funcs,
+		includes:   includes,
+		missingKey: missingKey,
+	}, nil
+}
+
+func (e *GoTemplateEngine) RenderString(text string, delimiters entity.Delimiters) (string, error) {
+	return e.RenderStringWith(text, delimiters, nil)
+}
+
+func (e *GoTemplateEngine) RenderStringWith(text string, delimiters entity.Delimiters, vars map[string]any) (string, error) {
+	tmpl, err := template.New("template").
+		Delims(delimiters.VariableStart, delimiters.VariableEnd).
+		Funcs(e.funcs).
+		Option("missingkey=" + e.missingKey).
+		Parse(text)
+	if err != nil {
+		return "", newTemplateError(text, err, e.params)
+	}
+
+	for name, include := range e.includes {
+		_, err = tmpl.New(name).Parse(include)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Undefined variables render empty when not strict
+	if e.missingKey == "zero" {
+		for _, named := range tmpl.Templates() {
+			lenientActions(named.Tree, named.Tree.Root)
+		}
+	}
+
+	data := e.data
+	if len(vars) > 0 {
+		data = maps.Clone(e.data)
+		maps.Copy(data, vars)
+	}
+
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return "", newTemplateError(text, err, e.params)
+	}
+	return buffer.String(), nil
+}
+
+// lenientValue renders missing keys (nil values) empty
+func lenientValue(value any) any {
+	if value == nil {
+		return ""
+	}
+	return value
+}
+
+// lenientActions pipes the actions printing values of a parsed template into lenientValue
+// (e.g. {{ .scaffold.missing }} is run as {{ .scaffold.missing | _scaffold_lenient }})
+func lenientActions(tree *parse.Tree, node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			lenientActions(tree, child)
+		}
+	case *parse.ActionNode:
+		// Variable declarations ({{ $name := ... }}) print nothing
+		if len(node.Pipe.Decl) > 0 {
+			return
+		}
+		identifier := parse.NewIdentifier(goTemplateLenientFunc).SetTree(tree).SetPos(node.Pos)
+		node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      node.Pos,
+			Args:     []parse.Node{identifier},
+		})
+	case *parse.IfNode:
+		lenientActions(tree, node.List)
+		lenientActions(tree, node.ElseList)
+	case *parse.RangeNode:
+		lenientActions(tree, node.List)
+		lenientActions(tree, node.ElseList)
+	case *parse.WithNode:
+		lenientActions(tree, node.List)
+		lenientActions(tree, node.ElseList)
+	}
+}
+
+func (e *GoTemplateEngine) EvaluateList(expression string, vars map[string]any) ([]any, error) {
+	result, err := e.RenderStringWith("{{ to_json ("+expression+") }}", entity.Delimiters{}, vars)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList(expression, result)
+}
+
+func (e *GoTemplateEngine) EvaluateCondition(expression string) (bool, error) {
+	result, err := e.RenderString("{{ if "+expression+" }}true{{ else }}false{{ end }}", entity.Delimiters{})
+	if err != nil {
+		return false, err
+	}
+	return result == "true", nil
+}
+
+func (e *GoTemplateEngine) Close() {}
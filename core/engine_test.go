@@ -0,0 +1,137 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+// engineConformanceCases must render the same way with every template engine
+// (each case is written in the syntax of each engine)
+var engineConformanceCases = []struct {
+	name       string
+	jinja      string
+	gotemplate string
+	expected   string
+}{
+	{
+		name:       "path without template",
+		jinja:      "project/README.md",
+		gotemplate: "project/README.md",
+		expected:   "project/README.md",
+	},
+	{
+		name:       "path with variable",
+		jinja:      "{{scaffold.project_name}}/main.go",
+		gotemplate: "{{.scaffold.project_name}}/main.go",
+		expected:   "My Service/main.go",
+	},
+	{
+		name:       "path with filters",
+		jinja:      "{{ scaffold.project_name | kebab_case }}/cmd/{{ scaffold.project_name | snake_case }}.go",
+		gotemplate: "{{ .scaffold.project_name | kebab_case }}/cmd/{{ .scaffold.project_name | snake_case }}.go",
+		expected:   "my-service/cmd/my_service.go",
+	},
+	{
+		name:       "path with condition",
+		jinja:      "project/{% if scaffold.use_docker == 'TRUE' %}docker{% endif %}/{% if scaffold.license == 'BSD-3' %}LICENSE{% endif %}",
+		gotemplate: `project/{{ if eq .scaffold.use_docker "TRUE" }}docker{{ end }}/{{ if eq .scaffold.license "BSD-3" }}LICENSE{{ end }}`,
+		expected:   "project/docker/",
+	},
+	{
+		name:       "content with filters",
+		jinja:      "package {{ scaffold.project_name | go_package }}\n\ntype {{ scaffold.project_name | go_exported }} struct{}",
+		gotemplate: "package {{ .scaffold.project_name | go_package }}\n\ntype {{ .scaffold.project_name | go_exported }} struct{}",
+		expected:   "package myservice\n\ntype MyService struct{}",
+	},
+	{
+		name:       "content with branches",
+		jinja:      "{% if scaffold.license == 'MIT' %}MIT License{% elif scaffold.license == 'BSD-3' %}BSD License{% else %}Other{% endif %}",
+		gotemplate: `{{ if eq .scaffold.license "MIT" }}MIT License{{ else if eq .scaffold.license "BSD-3" }}BSD License{{ else }}Other{{ end }}`,
+		expected:   "MIT License",
+	},
+	{
+		name:       "content with serialization",
+		jinja:      "{{ scaffold | to_json }}",
+		gotemplate: "{{ .scaffold | to_json }}",
+		expected:   `{"license":"MIT","project_name":"My Service","use_docker":"TRUE"}`,
+	},
+	{
+		name:       "content with builtin filters",
+		jinja:      "{{ scaffold.project_name | lower | replace(' ', '.') }} {{ scaffold.license | upper }}",
+		gotemplate: `{{ .scaffold.project_name | lower | replace " " "." }} {{ .scaffold.license | upper }}`,
+		expected:   "my.service MIT",
+	},
+}
+
+// engineConditionCases must be evaluated the same way with every template engine
+var engineConditionCases = []struct {
+	jinja      string
+	gotemplate string
+	expected   bool
+}{
+	{jinja: "scaffold.use_docker == 'TRUE'", gotemplate: `eq .scaffold.use_docker "TRUE"`, expected: true},
+	{jinja: "scaffold.license != 'MIT'", gotemplate: `ne .scaffold.license "MIT"`, expected: false},
+}
+
+func TestEngineConformance(t *testing.T) {
+	params := map[string]any{"project_name": "My Service", "use_docker": "TRUE", "license": "MIT"}
+
+	jinjaEngine, err := core.NewEngine(core.JinjaEngineName, params, core.EngineOptions{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewEngine(%q) failed: %v", core.JinjaEngineName, err)
+	}
+	defer jinjaEngine.Close()
+
+	goTemplateEngine, err := core.NewEngine(core.GoTemplateEngineName, params, core.EngineOptions{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewEngine(%q) failed: %v", core.GoTemplateEngineName, err)
+	}
+	defer goTemplateEngine.Close()
+
+	engines := []struct {
+		name     string
+		engine   core.Engine
+		template func(jinja string, gotemplate string) string
+	}{
+		{name: core.JinjaEngineName, engine: jinjaEngine, template: func(jinja string, gotemplate string) string { return jinja }},
+		{name: core.GoTemplateEngineName, engine: goTemplateEngine, template: func(jinja string, gotemplate string) string { return gotemplate }},
+	}
+
+	for _, e := range engines {
+		for _, tc := range engineConformanceCases {
+			t.Run(e.name+"/"+tc.name, func(t *testing.T) {
+				template := e.template(tc.jinja, tc.gotemplate)
+				actual, err := e.engine.RenderString(template, entity.Delimiters{})
+				if err != nil {
+					t.Fatalf("RenderString(%q) failed: %v", template, err)
+				}
+				if actual != tc.expected {
+					t.Errorf("RenderString(%q) = %q, expected %q", template, actual, tc.expected)
+				}
+			})
+		}
+
+		for _, tc := range engineConditionCases {
+			expression := e.template(tc.jinja, tc.gotemplate)
+			t.Run(e.name+"/condition "+expression, func(t *testing.T) {
+				actual, err := e.engine.EvaluateCondition(expression)
+				if err != nil {
+					t.Fatalf("EvaluateCondition(%q) failed: %v", expression, err)
+				}
+				if actual != tc.expected {
+					t.Errorf("EvaluateCondition(%q) = %t, expected %t", expression, actual, tc.expected)
+				}
+			})
+		}
+
+		t.Run(e.name+"/undefined variable", func(t *testing.T) {
+			template := e.template("{{ scaffold.projct_name }}", "{{ .scaffold.projct_name }}")
+			_, err := e.engine.RenderString(template, entity.Delimiters{})
+			if err == nil {
+				t.Errorf("RenderString(%q) expected an error", template)
+			}
+		})
+	}
+}
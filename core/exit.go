@@ -0,0 +1,40 @@
+package core
+
+import (
+	"os"
+	"sync"
+)
+
+var (
+	exitMutex    sync.Mutex
+	exitCleanups []func()
+)
+
+// OnExit registers a cleanup (e.g. removing a temporary clone) run by Exit, as os.Exit skips
+// deferred calls, and returns it so it can also be deferred (it only runs once)
+func OnExit(cleanup func()) func() {
+	once := sync.OnceFunc(cleanup)
+
+	exitMutex.Lock()
+	defer exitMutex.Unlock()
+	exitCleanups = append(exitCleanups, once)
+	return once
+}
+
+// Exit runs the registered cleanups (latest first) and exits with the given status code
+func Exit(code int) {
+	RunExitCleanups()
+	os.Exit(code)
+}
+
+// RunExitCleanups runs the registered cleanups (latest first) without exiting
+func RunExitCleanups() {
+	exitMutex.Lock()
+	cleanups := exitCleanups
+	exitCleanups = nil
+	exitMutex.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
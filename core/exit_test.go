@@ -0,0 +1,26 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestOnExit(t *testing.T) {
+	calls := []string{}
+	cleanupSource := core.OnExit(func() { calls = append(calls, "source") })
+	core.OnExit(func() { calls = append(calls, "base") })
+	cleanupHooks := core.OnExit(func() { calls = append(calls, "hooks") })
+
+	// Deferred cleanups already run are not run again when exiting
+	cleanupHooks()
+	core.RunExitCleanups()
+	cleanupSource()
+	core.RunExitCleanups()
+
+	expected := []string{"hooks", "base", "source"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("cleanups ran as %v, expected %v", calls, expected)
+	}
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/yaml.v3"
+)
+
+// Filter is a function that can be applied to values in templates (e.g. {{ scaffold.name | snake_case }})
+type Filter struct {
+	Name        string
+	Description string
+	Func        any
+}
+
+// Filters lists every filter available to templates
+var Filters = []Filter{
+	{Name: "snake_case", Description: "My Project -> my_project", Func: SnakeCase},
+	{Name: "camel_case", Description: "my_project -> myProject", Func: CamelCase},
+	{Name: "pascal_case", Description: "my_project -> MyProject", Func: PascalCase},
+	{Name: "kebab_case", Description: "MyProject -> my-project", Func: KebabCase},
+	{Name: "screaming_snake", Description: "myProject -> MY_PROJECT", Func: ScreamingSnake},
+	{Name: "title", Description: "my_project -> My Project", Func: Title},
+	{Name: "slugify", Description: "Héllo, World! -> hello-world", Func: Slugify},
+	{Name: "go_package", Description: "my-project -> myproject (valid Go package name)", Func: GoPackage},
+	{Name: "go_exported", Description: "my_handler -> MyHandler (exported Go identifier)", Func: GoExported},
+	{Name: "pluralize", Description: "handler -> handlers", Func: Pluralize},
+	{Name: "singularize", Description: "handlers -> handler", Func: Singularize},
+	{Name: "quote_yaml", Description: "it's: -> \"it's:\" (safe YAML string)", Func: QuoteYAML},
+	{Name: "to_json", Description: "value serialized as JSON", Func: ToJSON},
+	{Name: "to_yaml", Description: "value serialized as YAML", Func: ToYAML},
+	{Name: "to_toml", Description: "mapping serialized as TOML", Func: ToTOML},
+}
+
+// goKeywords cannot be used as Go package names
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true,
+	"if": true, "import": true, "interface": true, "map": true, "package": true, "range": true,
+	"return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
+
+// irregularPlurals are english nouns not following the pluralization rules
+var irregularPlurals = map[string]string{
+	"person": "people", "man": "men", "woman": "women", "child": "children", "mouse": "mice",
+	"goose": "geese", "tooth": "teeth", "foot": "feet", "ox": "oxen", "index": "indices",
+}
+
+// uncountableNouns are english nouns with the same singular and plural forms
+var uncountableNouns = map[string]bool{
+	"data": true, "equipment": true, "fish": true, "information": true, "metadata": true,
+	"news": true, "series": true, "sheep": true, "species": true, "software": true,
+}
+
+// SplitWords splits a string into words on separators and case changes (myHTTPServer -> my HTTP Server)
+func SplitWords(s string) []string {
+	runes := []rune(s)
+	words := []string{}
+	current := []rune{}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = []rune{}
+			}
+			continue
+		}
+
+		if len(current) > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				words = append(words, string(current))
+				current = []rune{}
+			}
+		}
+		current = append(current, r)
+	}
+
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) == 0 {
+		return word
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func joinWords(s string, separator string, transform func(string) string) string {
+	words := SplitWords(s)
+	for i, word := range words {
+		words[i] = transform(word)
+	}
+	return strings.Join(words, separator)
+}
+
+func SnakeCase(s string) string {
+	return joinWords(s, "_", strings.ToLower)
+}
+
+func KebabCase(s string) string {
+	return joinWords(s, "-", strings.ToLower)
+}
+
+func ScreamingSnake(s string) string {
+	return joinWords(s, "_", strings.ToUpper)
+}
+
+func PascalCase(s string) string {
+	return joinWords(s, "", capitalize)
+}
+
+func CamelCase(s string) string {
+	words := SplitWords(s)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = capitalize(word)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+func Title(s string) string {
+	return joinWords(s, " ", capitalize)
+}
+
+// Slugify lowercases and strips accents, replacing anything that isn't a letter/digit with dashes
+func Slugify(s string) string {
+	var builder strings.Builder
+	pendingDash := false
+	for _, r := range norm.NFKD.String(strings.ToLower(s)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingDash && builder.Len() > 0 {
+				builder.WriteRune('-')
+			}
+			builder.WriteRune(r)
+			pendingDash = false
+		case unicode.Is(unicode.Mn, r):
+			// combining marks (accents) are dropped
+		default:
+			pendingDash = true
+		}
+	}
+	return builder.String()
+}
+
+// GoPackage converts a string into a valid Go package name (lowercase letters and digits)
+func GoPackage(s string) string {
+	var builder strings.Builder
+	for _, r := range Slugify(s) {
+		if r != '-' {
+			builder.WriteRune(r)
+		}
+	}
+
+	name := builder.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) || goKeywords[name] {
+		name = "pkg" + name
+	}
+	return name
+}
+
+// GoExported converts a string into an exported Go identifier
+func GoExported(s string) string {
+	name := PascalCase(s)
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// matchCase applies the case of the first letter of word to replacement
+func matchCase(word string, replacement string) string {
+	if word != "" && unicode.IsUpper([]rune(word)[0]) {
+		return capitalize(replacement)
+	}
+	return replacement
+}
+
+func Pluralize(s string) string {
+	lower := strings.ToLower(s)
+	if s == "" || uncountableNouns[lower] {
+		return s
+	}
+	if plural, ok := irregularPlurals[lower]; ok {
+		return matchCase(s, plural)
+	}
+
+	for _, suffix := range []string{"s", "x", "z", "ch", "sh"} {
+		if strings.HasSuffix(lower, suffix) {
+			return s + "es"
+		}
+	}
+	if len(lower) > 1 && strings.HasSuffix(lower, "y") && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])) {
+		return s[:len(s)-1] + "ies"
+	}
+	return s + "s"
+}
+
+func Singularize(s string) string {
+	lower := strings.ToLower(s)
+	if s == "" || uncountableNouns[lower] {
+		return s
+	}
+	for singular, plural := range irregularPlurals {
+		if lower == plural {
+			return matchCase(s, singular)
+		}
+	}
+
+	switch {
+	case len(lower) > 3 && strings.HasSuffix(lower, "ies"):
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"), strings.HasSuffix(lower, "is"):
+		return s
+	case strings.HasSuffix(lower, "s"):
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// QuoteYAML quotes a value as a double quoted YAML string
+func QuoteYAML(value any) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(fmt.Sprint(value))
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func ToJSON(value any) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+func ToYAML(value any) (string, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	err := encoder.Encode(value)
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func ToTOML(value any) (string, error) {
+	data, err := toml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ApplyFilter applies the filter with the given name to a value
+func ApplyFilter(name string, value any) (string, error) {
+	for _, filter := range Filters {
+		if filter.Name != name {
+			continue
+		}
+
+		switch f := filter.Func.(type) {
+		case func(string) string:
+			return f(fmt.Sprint(value)), nil
+		case func(any) string:
+			return f(value), nil
+		case func(any) (string, error):
+			return f(value)
+		default:
+			return "", fmt.Errorf("filter %s has an unsupported signature", name)
+		}
+	}
+	return "", fmt.Errorf("unknown filter %s", name)
+}
@@ -0,0 +1,88 @@
+package core_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/kluctl/go-jinja2"
+)
+
+var filterTestCases = []struct {
+	filter   string
+	value    any
+	expected string
+}{
+	{filter: "snake_case", value: "My Project", expected: "my_project"},
+	{filter: "snake_case", value: "myHTTPServer2", expected: "my_http_server2"},
+	{filter: "snake_case", value: "already-kebab-case", expected: "already_kebab_case"},
+	{filter: "camel_case", value: "my_project_name", expected: "myProjectName"},
+	{filter: "camel_case", value: "HTTPServer", expected: "httpServer"},
+	{filter: "pascal_case", value: "my-project", expected: "MyProject"},
+	{filter: "pascal_case", value: "v2 api", expected: "V2Api"},
+	{filter: "kebab_case", value: "MyProjectName", expected: "my-project-name"},
+	{filter: "screaming_snake", value: "myProject", expected: "MY_PROJECT"},
+	{filter: "title", value: "my_project", expected: "My Project"},
+	{filter: "slugify", value: "Héllo, World!", expected: "hello-world"},
+	{filter: "slugify", value: "  --My  Project-- ", expected: "my-project"},
+	{filter: "go_package", value: "my-service", expected: "myservice"},
+	{filter: "go_package", value: "2fa", expected: "pkg2fa"},
+	{filter: "go_package", value: "type", expected: "pkgtype"},
+	{filter: "go_exported", value: "user_handler", expected: "UserHandler"},
+	{filter: "go_exported", value: "3d model", expected: "X3dModel"},
+	{filter: "pluralize", value: "handler", expected: "handlers"},
+	{filter: "pluralize", value: "category", expected: "categories"},
+	{filter: "pluralize", value: "day", expected: "days"},
+	{filter: "pluralize", value: "box", expected: "boxes"},
+	{filter: "pluralize", value: "Person", expected: "People"},
+	{filter: "pluralize", value: "data", expected: "data"},
+	{filter: "singularize", value: "handlers", expected: "handler"},
+	{filter: "singularize", value: "categories", expected: "category"},
+	{filter: "singularize", value: "classes", expected: "class"},
+	{filter: "singularize", value: "status", expected: "status"},
+	{filter: "singularize", value: "children", expected: "child"},
+	{filter: "quote_yaml", value: "it's: a \"test\"", expected: `"it's: a \"test\""`},
+	{filter: "to_json", value: map[string]any{"name": "svc", "tags": []any{"go", "<http>"}}, expected: `{"name":"svc","tags":["go","<http>"]}`},
+	{filter: "to_json", value: "svc", expected: `"svc"`},
+	{filter: "to_yaml", value: map[string]any{"name": "svc", "tags": []any{"go", "http"}}, expected: "name: svc\ntags:\n  - go\n  - http\n"},
+	{filter: "to_toml", value: map[string]any{"name": "svc", "owner": map[string]any{"team": "it's us"}}, expected: "name = 'svc'\n\n[owner]\nteam = \"it's us\"\n"},
+}
+
+func TestFilters(t *testing.T) {
+	for _, tc := range filterTestCases {
+		t.Run(fmt.Sprintf("%s(%v)", tc.filter, tc.value), func(t *testing.T) {
+			actual, err := core.ApplyFilter(tc.filter, tc.value)
+			if err != nil {
+				t.Fatalf("ApplyFilter(%q, %v) failed: %v", tc.filter, tc.value, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("ApplyFilter(%q, %v) = %q, expected %q", tc.filter, tc.value, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestJinjaFilters(t *testing.T) {
+	extensionOpts, err := core.JinjaExtensionOptions()
+	if err != nil {
+		t.Fatalf("JinjaExtensionOptions failed: %v", err)
+	}
+
+	jj, err := jinja2.NewJinja2("ScaffoldFiltersTest", 1, extensionOpts...)
+	if err != nil {
+		t.Fatalf("NewJinja2 failed: %v", err)
+	}
+	defer jj.Close()
+
+	for _, tc := range filterTestCases {
+		t.Run(fmt.Sprintf("%s(%v)", tc.filter, tc.value), func(t *testing.T) {
+			actual, err := jj.RenderString(fmt.Sprintf("{{ value | %s }}", tc.filter), jinja2.WithGlobal("value", tc.value))
+			if err != nil {
+				t.Fatalf("rendering filter %q failed: %v", tc.filter, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("filter %q on %v = %q, expected %q", tc.filter, tc.value, actual, tc.expected)
+			}
+		})
+	}
+}
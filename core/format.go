@@ -0,0 +1,220 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formatter reformats the content of generated files with one of its extensions
+type Formatter struct {
+	Name       string
+	Extensions []string
+	// Format returns the formatted content (or a *TemplateError locating invalid content)
+	Format func(data []byte) ([]byte, error)
+}
+
+// Formatters lists every formatter templates can enable with the format option
+var Formatters = []Formatter{
+	{Name: "go", Extensions: []string{".go"}, Format: FormatGo},
+	{Name: "json", Extensions: []string{".json"}, Format: FormatJSON},
+	{Name: "yaml", Extensions: []string{".yaml", ".yml"}, Format: FormatYAML},
+}
+
+var rgxYAMLErrorLine = regexp.MustCompile(`line (\d+): `)
+
+// CheckFormatters verifies every formatter enabled by a template exists
+func CheckFormatters(names []string) error {
+	for _, name := range names {
+		if FindFormatter(name) == nil {
+			return fmt.Errorf("unknown formatter %s", name)
+		}
+	}
+	return nil
+}
+
+// FindFormatter returns the formatter with the given name (nil when unknown)
+func FindFormatter(name string) *Formatter {
+	for i := range Formatters {
+		if Formatters[i].Name == name {
+			return &Formatters[i]
+		}
+	}
+	return nil
+}
+
+// FileFormatter returns the name of the enabled formatter handling a generated file (empty if none)
+func FileFormatter(names []string, renderedPath string) string {
+	extension := path.Ext(renderedPath)
+	for _, name := range names {
+		formatter := FindFormatter(name)
+		if formatter == nil {
+			continue
+		}
+		for _, formatterExtension := range formatter.Extensions {
+			if extension == formatterExtension {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// FormatContent formats rendered content with the named formatter, showing the
+// rendered line of content that could not be parsed in errors
+func FormatContent(name string, data []byte) ([]byte, error) {
+	formatter := FindFormatter(name)
+	if formatter == nil {
+		return nil, fmt.Errorf("unknown formatter %s", name)
+	}
+
+	// Files rendered empty (e.g. fully conditional content) are kept as is
+	if len(bytes.TrimSpace(data)) == 0 {
+		return data, nil
+	}
+
+	formatted, err := formatter.Format(data)
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
+		lines := strings.Split(string(data), "\n")
+		if templateErr.Line > 0 && templateErr.Line <= len(lines) {
+			templateErr.Snippet = strings.TrimRight(lines[templateErr.Line-1], "\r")
+		}
+	}
+	return formatted, err
+}
+
+// FormatGo formats Go source like gofmt (also sorting imports)
+func FormatGo(data []byte) ([]byte, error) {
+	formatted, err := format.Source(data)
+	if err == nil {
+		return formatted, nil
+	}
+
+	var errorList scanner.ErrorList
+	if errors.As(err, &errorList) && len(errorList) > 0 {
+		return nil, &TemplateError{
+			Line:    errorList[0].Pos.Line,
+			Column:  errorList[0].Pos.Column,
+			Message: "rendered Go code does not parse: " + errorList[0].Msg,
+		}
+	}
+	return nil, &TemplateError{Message: "rendered Go code does not parse: " + err.Error()}
+}
+
+// FormatJSON re-indents JSON with two spaces
+func FormatJSON(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := json.Indent(&buffer, data, "", "  ")
+	if err == nil {
+		// json.Indent keeps the trailing whitespace of the content (e.g. its final newline)
+		return append(bytes.TrimRight(buffer.Bytes(), " \t\r\n"), '\n'), nil
+	}
+
+	templateErr := &TemplateError{Message: "rendered JSON does not parse: " + err.Error()}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		consumed := data[:min(int(syntaxErr.Offset), len(data))]
+		templateErr.Line = bytes.Count(consumed, []byte("\n")) + 1
+		templateErr.Column = len(consumed) - bytes.LastIndexByte(consumed, '\n') - 1
+	}
+	return nil, templateErr
+}
+
+// FormatYAML normalizes YAML indentation to two spaces (keeping comments, the leading document
+// marker and the blank lines between top-level keys)
+func FormatYAML(data []byte) ([]byte, error) {
+	sourceLines := strings.Split(string(data), "\n")
+	documents := []string{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			templateErr := &TemplateError{Message: "rendered YAML does not parse: " + err.Error()}
+			if match := rgxYAMLErrorLine.FindStringSubmatch(err.Error()); match != nil {
+				templateErr.Line, _ = strconv.Atoi(match[1])
+			}
+			return nil, templateErr
+		}
+
+		var buffer bytes.Buffer
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+		err = encoder.Encode(&document)
+		if err == nil {
+			err = encoder.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, restoreYAMLBlankLines(buffer.String(), yamlBlankLineKeys(&document, sourceLines)))
+	}
+
+	formatted := strings.Join(documents, "---\n")
+	if strings.HasPrefix(strings.TrimLeft(string(data), " \t\r\n"), "---") {
+		formatted = "---\n" + formatted
+	}
+	return []byte(formatted), nil
+}
+
+// yamlBlankLineKeys returns whether each top-level key of a document follows a blank line (or
+// the head comment of the key does)
+func yamlBlankLineKeys(document *yaml.Node, sourceLines []string) []bool {
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode || document.Content[0].Style&yaml.FlowStyle != 0 {
+		return nil
+	}
+
+	mapping := document.Content[0]
+	blankLines := []bool{}
+	for i := 0; i < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		firstLine := key.Line
+		if key.HeadComment != "" {
+			firstLine -= strings.Count(key.HeadComment, "\n") + 1
+		}
+		// Lines are numbered from 1, the previous line is at index firstLine-2
+		blankLines = append(blankLines, i > 0 && firstLine >= 2 && strings.TrimSpace(sourceLines[firstLine-2]) == "")
+	}
+	return blankLines
+}
+
+// restoreYAMLBlankLines inserts a blank line before the top-level keys (and their head comment)
+// of an encoded document that followed one
+func restoreYAMLBlankLines(encoded string, blankLines []bool) string {
+	lines := strings.SplitAfter(encoded, "\n")
+	result := make([]string, 0, len(lines))
+	key := 0
+	for _, line := range lines {
+		// Top-level keys are the only lines starting at the first column (besides comments)
+		if line != "" && !strings.ContainsRune(" \t\n#-", rune(line[0])) {
+			if key < len(blankLines) && blankLines[key] {
+				insertAt := len(result)
+				for insertAt > 0 && strings.HasPrefix(result[insertAt-1], "#") {
+					insertAt--
+				}
+				if insertAt > 0 && result[insertAt-1] != "\n" {
+					result = slices.Insert(result, insertAt, "\n")
+				}
+			}
+			key++
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "")
+}
@@ -0,0 +1,139 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestFileFormatter(t *testing.T) {
+	testCases := []struct {
+		names        []string
+		renderedPath string
+		expected     string
+	}{
+		{names: []string{"go"}, renderedPath: "project/main.go", expected: "go"},
+		{names: []string{"go"}, renderedPath: "project/go.mod", expected: ""},
+		{names: []string{"go", "yaml"}, renderedPath: "project/config.yml", expected: "yaml"},
+		{names: []string{"json"}, renderedPath: "project/package.json", expected: "json"},
+		{names: []string{}, renderedPath: "project/main.go", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.renderedPath, func(t *testing.T) {
+			actual := core.FileFormatter(tc.names, tc.renderedPath)
+			if actual != tc.expected {
+				t.Errorf("FileFormatter(%v, %q) = %q, expected %q", tc.names, tc.renderedPath, actual, tc.expected)
+			}
+		})
+	}
+
+	if err := core.CheckFormatters([]string{"go", "prettier"}); err == nil {
+		t.Errorf("CheckFormatters accepted an unknown formatter")
+	}
+}
+
+func TestFormatContent(t *testing.T) {
+	testCases := []struct {
+		name            string
+		formatter       string
+		content         string
+		expected        string
+		expectedLine    int
+		expectedSnippet string
+	}{
+		{
+			name:      "go blank lines and imports",
+			formatter: "go",
+			content:   "package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\n\n\nfunc main() {\nfmt.Println(os.Args)\n}\n",
+			expected:  "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() {\n\tfmt.Println(os.Args)\n}\n",
+		},
+		{
+			name:            "go parse error",
+			formatter:       "go",
+			content:         "package main\n\nfunc main() {\n\tx := \n}\n",
+			expectedLine:    5,
+			expectedSnippet: "}",
+		},
+		{
+			name:      "empty content",
+			formatter: "go",
+			content:   "\n",
+			expected:  "\n",
+		},
+		{
+			name:      "json",
+			formatter: "json",
+			content:   `{"name": "svc", "tags": ["a","b"]}`,
+			expected:  "{\n  \"name\": \"svc\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n",
+		},
+		{
+			name:      "json trailing newline",
+			formatter: "json",
+			content:   "{\"name\": \"svc\"}\n",
+			expected:  "{\n  \"name\": \"svc\"\n}\n",
+		},
+		{
+			name:      "json trailing crlf",
+			formatter: "json",
+			content:   "{\"name\": \"svc\"}\r\n\r\n",
+			expected:  "{\n  \"name\": \"svc\"\n}\n",
+		},
+		{
+			name:            "json parse error",
+			formatter:       "json",
+			content:         "{\n  \"name\": \"svc\",\n}",
+			expectedLine:    3,
+			expectedSnippet: "}",
+		},
+		{
+			name:      "yaml",
+			formatter: "yaml",
+			content:   "# service\nname: svc\ntags:\n    - a\n    - b\n",
+			expected:  "# service\nname: svc\ntags:\n  - a\n  - b\n",
+		},
+		{
+			name:      "yaml document marker and blank lines",
+			formatter: "yaml",
+			content:   "---\nname: svc\n\n# deployment\nreplicas: 2\nports:\n    - 80\n\n\nenv:\n    DEBUG: \"1\"\n",
+			expected:  "---\nname: svc\n\n# deployment\nreplicas: 2\nports:\n  - 80\n\nenv:\n  DEBUG: \"1\"\n",
+		},
+		{
+			name:      "yaml documents",
+			formatter: "yaml",
+			content:   "kind: Service\n\nspec: {}\n---\nkind: Deployment\nspec:\n    replicas: 2\n",
+			expected:  "kind: Service\n\nspec: {}\n---\nkind: Deployment\nspec:\n  replicas: 2\n",
+		},
+		{
+			name:            "yaml parse error",
+			formatter:       "yaml",
+			content:         "name: svc\n  port: 80\n",
+			expectedLine:    2,
+			expectedSnippet: "  port: 80",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.FormatContent(tc.formatter, []byte(tc.content))
+			if tc.expectedLine == 0 {
+				if err != nil {
+					t.Fatalf("FormatContent(%q) failed: %v", tc.formatter, err)
+				}
+				if string(actual) != tc.expected {
+					t.Errorf("FormatContent(%q) = %q, expected %q", tc.formatter, actual, tc.expected)
+				}
+				return
+			}
+
+			var templateErr *core.TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("FormatContent(%q) error = %v, expected a *core.TemplateError", tc.formatter, err)
+			}
+			if templateErr.Line != tc.expectedLine || templateErr.Snippet != tc.expectedSnippet {
+				t.Errorf("FormatContent(%q) error at line %d (%q), expected line %d (%q)", tc.formatter, templateErr.Line, templateErr.Snippet, tc.expectedLine, tc.expectedSnippet)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"path"
+
+	"github.com/gobwas/glob"
+)
+
+// MatchAnyGlob returns whether a slash separated path matches any of the glob patterns
+func MatchAnyGlob(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return false, err
+		}
+		if g.Match(name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MatchAnyGlobOrParent is like MatchAnyGlob but also matches paths inside a matching folder
+func MatchAnyGlobOrParent(patterns []string, name string) (bool, error) {
+	for current := name; current != "." && current != "/" && current != ""; current = path.Dir(current) {
+		matched, err := MatchAnyGlob(patterns, current)
+		if err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
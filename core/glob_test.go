@@ -0,0 +1,141 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/afero"
+)
+
+func TestMatchAnyGlob(t *testing.T) {
+	patterns := []string{"*.png", "**/*.min.js", "docs/*.md"}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{name: "top-level extension", path: "logo.png", expected: true},
+		{name: "star does not cross folders", path: "assets/logo.png", expected: false},
+		{name: "double star crosses folders", path: "web/static/app.min.js", expected: true},
+		{name: "double star needs a folder", path: "app.min.js", expected: false},
+		{name: "folder prefix", path: "docs/index.md", expected: true},
+		{name: "folder prefix nested", path: "docs/api/index.md", expected: false},
+		{name: "no match", path: "main.go", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.MatchAnyGlob(patterns, tc.path)
+			if err != nil {
+				t.Fatalf("MatchAnyGlob(%q) failed: %v", tc.path, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("MatchAnyGlob(%q) = %t, expected %t", tc.path, actual, tc.expected)
+			}
+		})
+	}
+
+	_, err := core.MatchAnyGlob([]string{"[invalid"}, "main.go")
+	if err == nil {
+		t.Errorf("MatchAnyGlob with an invalid pattern did not fail")
+	}
+}
+
+func TestMatchAnyGlobOrParent(t *testing.T) {
+	// copy_without_render patterns are matched against template paths (names not rendered yet)
+	patterns := []string{"*/assets", "vendor", "**/*.tmpl"}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{name: "folder itself", path: "{{ scaffold.name }}/assets", expected: true},
+		{name: "file inside folder", path: "{{ scaffold.name }}/assets/logo.svg", expected: true},
+		{name: "nested file inside folder", path: "{{ scaffold.name }}/assets/img/{{ scaffold.name }}.svg", expected: true},
+		{name: "top-level folder", path: "vendor/github.com/pkg/errors/errors.go", expected: true},
+		{name: "file glob", path: "{{ scaffold.name }}/templates/index.tmpl", expected: true},
+		{name: "sibling folder", path: "{{ scaffold.name }}/assets-old/logo.svg", expected: false},
+		{name: "folder nested too deep", path: "{{ scaffold.name }}/web/assets/logo.svg", expected: false},
+		{name: "parent of the folder", path: "{{ scaffold.name }}", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.MatchAnyGlobOrParent(patterns, tc.path)
+			if err != nil {
+				t.Fatalf("MatchAnyGlobOrParent(%q) failed: %v", tc.path, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("MatchAnyGlobOrParent(%q) = %t, expected %t", tc.path, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCopyWithoutRenderFolder(t *testing.T) {
+	templateRoot := t.TempDir()
+	templatePath := "{{ .scaffold.name }}/assets/{{ .scaffold.name }}.txt"
+	content := "{{ .scaffold.name }}\n"
+	writeTestFiles(t, templateRoot, map[string]string{templatePath: content})
+
+	engine, err := core.NewEngine(core.GoTemplateEngineName, map[string]any{"name": "svc"}, core.EngineOptions{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+	defer engine.Close()
+
+	// The whole folder is copied without render...
+	copyWithoutRender, err := core.MatchAnyGlobOrParent([]string{"*/assets"}, templatePath)
+	if err != nil {
+		t.Fatalf("MatchAnyGlobOrParent failed: %v", err)
+	}
+	if !copyWithoutRender {
+		t.Fatalf("MatchAnyGlobOrParent(%q) = false, expected true", templatePath)
+	}
+
+	// ...but the names of its paths are still rendered
+	renderedPath, err := engine.RenderString(templatePath, entity.Delimiters{})
+	if err != nil {
+		t.Fatalf("RenderString(%q) failed: %v", templatePath, err)
+	}
+	if renderedPath != "svc/assets/svc.txt" {
+		t.Errorf("RenderString(%q) = %q, expected %q", templatePath, renderedPath, "svc/assets/svc.txt")
+	}
+
+	sourcePath := filepath.Join(templateRoot, templatePath)
+	info, err := os.Lstat(sourcePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := entity.PlanEntry{
+		TemplatePath:      templatePath,
+		RenderedPath:      renderedPath,
+		SourcePath:        sourcePath,
+		Info:              info,
+		Mode:              info.Mode(),
+		CopyWithoutRender: copyWithoutRender,
+	}
+
+	fs := afero.NewMemMapFs()
+	err = fs.MkdirAll("/out/svc/assets", 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = core.GenerateFile(fs, entry, "/out/"+renderedPath, engine)
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	actual, err := afero.ReadFile(fs, "/out/svc/assets/svc.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != content {
+		t.Errorf("copied content = %q, expected %q", actual, content)
+	}
+}
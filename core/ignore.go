@@ -0,0 +1,56 @@
+package core
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// IgnoreFileName lists (with gitignore syntax) the template paths excluded from generation
+const IgnoreFileName = ".scaffoldignore"
+
+// BuiltinIgnorePatterns are excluded from every template (git metadata of cloned sources and
+// checked out templates)
+var BuiltinIgnorePatterns = []string{".git"}
+
+// LoadIgnoreMatcher parses the ignore file found at the template root (if any)
+func LoadIgnoreMatcher(rootPath string) (gitignore.Matcher, error) {
+	patterns := []gitignore.Pattern{}
+	for _, pattern := range BuiltinIgnorePatterns {
+		patterns = append(patterns, gitignore.ParsePattern(pattern, nil))
+	}
+
+	ignoreFile, err := os.Open(filepath.Join(rootPath, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return gitignore.NewMatcher(patterns), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer ignoreFile.Close()
+
+	scanner := bufio.NewScanner(ignoreFile)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return gitignore.NewMatcher(patterns), nil
+}
+
+// IsIgnored returns whether a slash separated template path is excluded by the matcher
+func IsIgnored(matcher gitignore.Matcher, relativePath string, isDir bool) bool {
+	if relativePath == "" {
+		return false
+	}
+	return matcher.Match(strings.Split(relativePath, "/"), isDir)
+}
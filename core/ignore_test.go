@@ -0,0 +1,111 @@
+package core_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestIsIgnored(t *testing.T) {
+	rootPath := t.TempDir()
+	ignoreContent := "# editor files\n.idea/\n*.swp\nnode_modules/\n/README.template-dev.md\n!keep.swp\n"
+	if err := os.WriteFile(filepath.Join(rootPath, core.IgnoreFileName), []byte(ignoreContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	matcher, err := core.LoadIgnoreMatcher(rootPath)
+	if err != nil {
+		t.Fatalf("LoadIgnoreMatcher failed: %v", err)
+	}
+
+	testCases := []struct {
+		relativePath string
+		isDir        bool
+		expected     bool
+	}{
+		{relativePath: ".idea", isDir: true, expected: true},
+		{relativePath: ".idea", isDir: false, expected: false},
+		{relativePath: "project/main.go.swp", expected: true},
+		{relativePath: "project/keep.swp", expected: false},
+		{relativePath: "project/web/node_modules", isDir: true, expected: true},
+		{relativePath: "README.template-dev.md", expected: true},
+		{relativePath: "project/README.template-dev.md", expected: false},
+		{relativePath: "project/main.go", expected: false},
+		{relativePath: ".git", isDir: true, expected: true},
+		{relativePath: "project/vendor/lib/.git", expected: true},
+		{relativePath: "project/.gitignore", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.relativePath, func(t *testing.T) {
+			actual := core.IsIgnored(matcher, tc.relativePath, tc.isDir)
+			if actual != tc.expected {
+				t.Errorf("IsIgnored(%q, %t) = %t, expected %t", tc.relativePath, tc.isDir, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIgnoreClonedSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required to clone local repositories")
+	}
+
+	repositoryPath := t.TempDir()
+	writeTestFiles(t, repositoryPath, map[string]string{
+		"scaffold.yaml":                  "prompt: {}\n",
+		"{{ scaffold.name }}/main.go":    "package main\n",
+		"{{ scaffold.name }}/.gitignore": "bin/\n",
+	})
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=scaffold", "-c", "user.email=scaffold@example.com", "commit", "-q", "-m", "template"},
+	} {
+		command := exec.Command("git", args...)
+		command.Dir = repositoryPath
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	clonePath := t.TempDir()
+	_, err := core.CloneRepository("file://"+repositoryPath, clonePath)
+	if err != nil {
+		t.Fatalf("CloneRepository failed: %v", err)
+	}
+
+	matcher, err := core.LoadIgnoreMatcher(clonePath)
+	if err != nil {
+		t.Fatalf("LoadIgnoreMatcher failed: %v", err)
+	}
+
+	// Walked like the template layers are planned
+	walked := []string{}
+	err = filepath.Walk(clonePath, func(pathValue string, info os.FileInfo, err error) error {
+		if err != nil || pathValue == clonePath {
+			return err
+		}
+		relativePath := core.DeltaRelativePath(clonePath, pathValue)
+		if core.IsIgnored(matcher, relativePath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		walked = append(walked, relativePath)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"scaffold.yaml", "{{ scaffold.name }}", "{{ scaffold.name }}/.gitignore", "{{ scaffold.name }}/main.go"}
+	if !reflect.DeepEqual(walked, expected) {
+		t.Errorf("walked %v, expected %v", walked, expected)
+	}
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/copito/goscaffold/entity"
+)
+
+// DefaultIncludesDir is the folder of a template holding the files shared through
+// include/import/extends (it is never generated)
+const DefaultIncludesDir = "_includes"
+
+// IncludesDir returns the includes folder of a template layer (slash separated, relative to its root)
+func IncludesDir(config entity.TemplateConfig) (string, error) {
+	dir := config.IncludesDir
+	if dir == "" {
+		dir = DefaultIncludesDir
+	}
+
+	if !filepath.IsLocal(dir) {
+		return "", fmt.Errorf("includes_dir %s must be a folder inside the template", dir)
+	}
+	return filepath.ToSlash(filepath.Clean(dir)), nil
+}
+
+// IncludeDirs returns the absolute includes folders of the template layers that have one
+// (extending templates come first so their files take precedence over the base template ones)
+func IncludeDirs(layers []entity.TemplateLayer) ([]string, error) {
+	dirs := []string{}
+	for i := len(layers) - 1; i >= 0; i-- {
+		dir, err := IncludesDir(layers[i].Config)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", layers[i].RootPath, err)
+		}
+
+		includesPath, err := filepath.Abs(filepath.Join(layers[i].RootPath, dir))
+		if err != nil {
+			return nil, err
+		}
+
+		stat, err := os.Stat(includesPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !stat.IsDir() {
+			return nil, fmt.Errorf("includes folder %s is not a directory", includesPath)
+		}
+		dirs = append(dirs, includesPath)
+	}
+	return dirs, nil
+}
+
+// readIncludes reads every file of the includes folders by their slash separated path
+// relative to their folder (files of the first folders take precedence)
+func readIncludes(dirs []string) (map[string]string, error) {
+	includes := map[string]string{}
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(pathValue string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+
+			name := DeltaRelativePath(dir, pathValue)
+			if _, found := includes[name]; found {
+				return nil
+			}
+
+			data, err := os.ReadFile(pathValue)
+			if err != nil {
+				return err
+			}
+			includes[name] = string(data)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return includes, nil
+}
@@ -0,0 +1,129 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		filePath := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+}
+
+func TestIncludesDir(t *testing.T) {
+	testCases := []struct {
+		includesDir string
+		expected    string
+		expectErr   bool
+	}{
+		{includesDir: "", expected: core.DefaultIncludesDir},
+		{includesDir: "shared/partials/", expected: "shared/partials"},
+		{includesDir: "../shared", expectErr: true},
+		{includesDir: "/shared", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.includesDir, func(t *testing.T) {
+			actual, err := core.IncludesDir(entity.TemplateConfig{IncludesDir: tc.includesDir})
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("IncludesDir(%q) error = %v, expected error %t", tc.includesDir, err, tc.expectErr)
+			}
+			if actual != tc.expected {
+				t.Errorf("IncludesDir(%q) = %q, expected %q", tc.includesDir, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEngineIncludes(t *testing.T) {
+	testCases := []struct {
+		engine    string
+		baseFiles map[string]string
+		// the extending template uses "partials" as includes folder
+		childFiles map[string]string
+		templates  map[string]string
+	}{
+		{
+			engine: core.JinjaEngineName,
+			baseFiles: map[string]string{
+				"_includes/license.j2":    "Licensed under {{ scaffold.license }}",
+				"_includes/header.j2":     "base header",
+				"_includes/macros.j2":     "{% macro greet(name) %}Hello {{ name }}{% endmacro %}",
+				"_includes/layout.j2":     "<{% block body %}{% endblock %}>",
+				"_includes/nested/foo.j2": "nested {{ scaffold.name }}",
+			},
+			childFiles: map[string]string{"partials/header.j2": "child header"},
+			templates: map[string]string{
+				`{% include "license.j2" %}`:                                                 "Licensed under MIT",
+				`{% include "nested/foo.j2" %}`:                                              "nested svc",
+				`{% include "header.j2" %}`:                                                  "child header",
+				`{% import "macros.j2" as m %}{{ m.greet(scaffold.name) }}`:                  "Hello svc",
+				`{% extends "layout.j2" %}{% block body %}{{ scaffold.name }}{% endblock %}`: "<svc>",
+			},
+		},
+		{
+			engine: core.GoTemplateEngineName,
+			baseFiles: map[string]string{
+				"_includes/license.tmpl":    "Licensed under {{ .scaffold.license }}",
+				"_includes/header.tmpl":     "base header",
+				"_includes/nested/foo.tmpl": "nested {{ .scaffold.name }}",
+			},
+			childFiles: map[string]string{"partials/header.tmpl": "child header"},
+			templates: map[string]string{
+				`{{ template "license.tmpl" . }}`:    "Licensed under MIT",
+				`{{ template "nested/foo.tmpl" . }}`: "nested svc",
+				`{{ template "header.tmpl" . }}`:     "child header",
+			},
+		},
+	}
+
+	params := map[string]any{"name": "svc", "license": "MIT"}
+	for _, tc := range testCases {
+		base := t.TempDir()
+		child := t.TempDir()
+		writeTestFiles(t, base, tc.baseFiles)
+		writeTestFiles(t, child, tc.childFiles)
+
+		layers := []entity.TemplateLayer{
+			{RootPath: base},
+			{RootPath: child, Config: entity.TemplateConfig{IncludesDir: "partials"}},
+		}
+		includeDirs, err := core.IncludeDirs(layers)
+		if err != nil {
+			t.Fatalf("IncludeDirs failed: %v", err)
+		}
+		if len(includeDirs) != 2 || filepath.Base(includeDirs[0]) != "partials" {
+			t.Fatalf("IncludeDirs = %v, expected the extending template includes folder first", includeDirs)
+		}
+
+		engine, err := core.NewEngine(tc.engine, params, core.EngineOptions{Workers: 1, IncludeDirs: includeDirs})
+		if err != nil {
+			t.Fatalf("NewEngine(%q) failed: %v", tc.engine, err)
+		}
+		defer engine.Close()
+
+		for template, expected := range tc.templates {
+			t.Run(tc.engine+"/"+template, func(t *testing.T) {
+				actual, err := engine.RenderString(template, entity.Delimiters{})
+				if err != nil {
+					t.Fatalf("RenderString(%q) failed: %v", template, err)
+				}
+				if actual != expected {
+					t.Errorf("RenderString(%q) = %q, expected %q", template, actual, expected)
+				}
+			})
+		}
+	}
+}
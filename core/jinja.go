@@ -0,0 +1,145 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/copito/goscaffold/entity"
+	"github.com/kluctl/go-jinja2"
+)
+
+// jinjaExtensionsSource registers the filters and custom delimiters on the Jinja environment
+//
+//go:embed jinja_extensions.py
+var jinjaExtensionsSource []byte
+
+// JinjaExtensionOptions registers the scaffold extensions (filters, delimiters) on a Jinja environment
+func JinjaExtensionOptions() ([]jinja2.Jinja2Opt, error) {
+	modulePath, err := extractJinjaExtensions()
+	if err != nil {
+		return nil, err
+	}
+
+	return []jinja2.Jinja2Opt{
+		jinja2.WithPythonPath(modulePath),
+		jinja2.WithExtension("scaffold_extensions.ScaffoldFiltersExtension"),
+		jinja2.WithExtension("scaffold_extensions.ScaffoldDelimitersExtension"),
+	}, nil
+}
+
+// extractJinjaExtensions writes the python module once per content (like the embedded jinja2 library)
+// to a private folder of the user cache, an existing module only being used if it holds the embedded content
+func extractJinjaExtensions() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating the user cache folder: %w", err)
+	}
+
+	hash := sha256.Sum256(jinjaExtensionsSource)
+	modulePath := filepath.Join(cacheDir, "goscaffold", "jinja2-extensions-"+hex.EncodeToString(hash[:8]))
+	moduleFile := filepath.Join(modulePath, "scaffold_extensions.py")
+
+	err = os.MkdirAll(modulePath, os.FileMode(0o700))
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(moduleFile)
+	if err == nil && bytes.Equal(content, jinjaExtensionsSource) {
+		return modulePath, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	// Missing or modified: (re)written atomically so concurrent runs never load a partial module
+	tmpFile, err := os.CreateTemp(modulePath, "scaffold_extensions-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmpFile.Write(jinjaExtensionsSource)
+	tmpFile.Close()
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+
+	err = os.Rename(tmpFile.Name(), moduleFile)
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+	return modulePath, nil
+}
+
+// JinjaEngine renders templates with Jinja2 (running on an embedded python runtime)
+type JinjaEngine struct {
+	jj     *jinja2.Jinja2
+	params map[string]any
+}
+
+// NewJinjaEngine starts a Jinja2 engine able to run options.Workers renders at the same time
+// (one python worker each), loading included/imported/extended templates from the include folders
+func NewJinjaEngine(params map[string]any, options EngineOptions) (*JinjaEngine, error) {
+	parallelism := max(options.Workers, 1)
+
+	// Extensions available to templates (filters like snake_case, custom delimiters...)
+	extensionOpts, err := JinjaExtensionOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := append(extensionOpts,
+		jinja2.WithGlobal("scaffold", params),
+		jinja2.WithSearchDirs(options.IncludeDirs),
+		jinja2.WithStrict(!options.LenientUndefined),
+	)
+	jj, err := jinja2.NewJinja2("FolderFileName", parallelism, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JinjaEngine{jj: jj, params: params}, nil
+}
+
+func (e *JinjaEngine) RenderString(template string, delimiters entity.Delimiters) (string, error) {
+	return e.RenderStringWith(template, delimiters, nil)
+}
+
+func (e *JinjaEngine) RenderStringWith(template string, delimiters entity.Delimiters, vars map[string]any) (string, error) {
+	opts := DelimiterOptions(delimiters)
+	for name, value := range vars {
+		opts = append(opts, jinja2.WithGlobal(name, value))
+	}
+
+	result, err := e.jj.RenderString(ForceRenderMarker(delimiters, template)+template, opts...)
+	if err != nil {
+		return "", newTemplateError(template, err, e.params)
+	}
+	return result, nil
+}
+
+func (e *JinjaEngine) EvaluateCondition(expression string) (bool, error) {
+	result, err := e.jj.RenderString("{% if " + expression + " %}true{% else %}false{% endif %}")
+	if err != nil {
+		return false, err
+	}
+	return result == "true", nil
+}
+
+func (e *JinjaEngine) EvaluateList(expression string, vars map[string]any) ([]any, error) {
+	result, err := e.RenderStringWith("{{ ("+expression+") | list | tojson }}", entity.Delimiters{}, vars)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList(expression, result)
+}
+
+func (e *JinjaEngine) Close() {
+	e.jj.Close()
+}
@@ -0,0 +1,246 @@
+# Jinja extensions of scaffold: filters (mirrors core/filters.go, both must behave the same)
+# and custom delimiters (see core/delimiters.go)
+import json
+import unicodedata
+
+import yaml
+from jinja2.ext import Extension
+
+GO_KEYWORDS = {
+    "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go",
+    "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type",
+    "var",
+}
+
+IRREGULAR_PLURALS = {
+    "person": "people", "man": "men", "woman": "women", "child": "children", "mouse": "mice",
+    "goose": "geese", "tooth": "teeth", "foot": "feet", "ox": "oxen", "index": "indices",
+}
+
+UNCOUNTABLE_NOUNS = {
+    "data", "equipment", "fish", "information", "metadata", "news", "series", "sheep", "species", "software",
+}
+
+
+def split_words(s):
+    s = str(s)
+    words = []
+    current = ""
+    for i, c in enumerate(s):
+        if not (c.isalpha() or c.isdecimal()):
+            if current:
+                words.append(current)
+                current = ""
+            continue
+
+        if current and c.isupper():
+            previous = s[i - 1]
+            next_is_lower = i + 1 < len(s) and s[i + 1].islower()
+            if previous.islower() or previous.isdecimal() or (previous.isupper() and next_is_lower):
+                words.append(current)
+                current = ""
+        current += c
+
+    if current:
+        words.append(current)
+    return words
+
+
+def capitalize(word):
+    word = word.lower()
+    return word[:1].upper() + word[1:]
+
+
+def snake_case(s):
+    return "_".join(w.lower() for w in split_words(s))
+
+
+def kebab_case(s):
+    return "-".join(w.lower() for w in split_words(s))
+
+
+def screaming_snake(s):
+    return "_".join(w.upper() for w in split_words(s))
+
+
+def pascal_case(s):
+    return "".join(capitalize(w) for w in split_words(s))
+
+
+def camel_case(s):
+    words = split_words(s)
+    return "".join(w.lower() if i == 0 else capitalize(w) for i, w in enumerate(words))
+
+
+def title(s):
+    return " ".join(capitalize(w) for w in split_words(s))
+
+
+def slugify(s):
+    result = ""
+    pending_dash = False
+    for c in unicodedata.normalize("NFKD", str(s).lower()):
+        if ord(c) < 127 and (c.isalpha() or c.isdecimal()):
+            if pending_dash and result:
+                result += "-"
+            result += c
+            pending_dash = False
+        elif unicodedata.category(c) == "Mn":
+            # combining marks (accents) are dropped
+            pass
+        else:
+            pending_dash = True
+    return result
+
+
+def go_package(s):
+    name = slugify(s).replace("-", "")
+    if name == "" or name[0].isdecimal() or name in GO_KEYWORDS:
+        name = "pkg" + name
+    return name
+
+
+def go_exported(s):
+    name = pascal_case(s)
+    if name == "" or not name[0].isalpha():
+        name = "X" + name
+    return name
+
+
+def match_case(word, replacement):
+    if word and word[0].isupper():
+        return capitalize(replacement)
+    return replacement
+
+
+def pluralize(s):
+    s = str(s)
+    lower = s.lower()
+    if s == "" or lower in UNCOUNTABLE_NOUNS:
+        return s
+    if lower in IRREGULAR_PLURALS:
+        return match_case(s, IRREGULAR_PLURALS[lower])
+
+    for suffix in ("s", "x", "z", "ch", "sh"):
+        if lower.endswith(suffix):
+            return s + "es"
+    if len(lower) > 1 and lower.endswith("y") and lower[-2] not in "aeiou":
+        return s[:-1] + "ies"
+    return s + "s"
+
+
+def singularize(s):
+    s = str(s)
+    lower = s.lower()
+    if s == "" or lower in UNCOUNTABLE_NOUNS:
+        return s
+    for singular, plural in IRREGULAR_PLURALS.items():
+        if lower == plural:
+            return match_case(s, singular)
+
+    if len(lower) > 3 and lower.endswith("ies"):
+        return s[:-3] + "y"
+    if lower.endswith(("sses", "xes", "zes", "ches", "shes")):
+        return s[:-2]
+    if lower.endswith(("ss", "us", "is")):
+        return s
+    if lower.endswith("s"):
+        return s[:-1]
+    return s
+
+
+def quote_yaml(value):
+    return json.dumps(str(value), ensure_ascii=False)
+
+
+def to_json(value):
+    return json.dumps(value, ensure_ascii=False, sort_keys=True, separators=(",", ":"))
+
+
+class IndentedDumper(yaml.SafeDumper):
+    # sequences inside mappings are indented (like go yaml.v3)
+    def increase_indent(self, flow=False, indentless=False):
+        return super().increase_indent(flow, False)
+
+
+def to_yaml(value):
+    result = yaml.dump(value, Dumper=IndentedDumper, default_flow_style=False, sort_keys=True, allow_unicode=True)
+    if result.endswith("\n...\n"):
+        result = result[:-4]
+    return result
+
+
+def toml_string(s):
+    if "'" not in s and not any(ord(c) < 32 or ord(c) == 127 for c in s):
+        return "'" + s + "'"
+    return json.dumps(s, ensure_ascii=False)
+
+
+def toml_value(value):
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, (int, float)):
+        return repr(value)
+    if isinstance(value, (list, tuple)):
+        return "[" + ", ".join(toml_value(v) for v in value) + "]"
+    return toml_string(str(value))
+
+
+def toml_table(value, prefix):
+    lines = []
+    tables = []
+    for key in sorted(value):
+        if isinstance(value[key], dict):
+            tables.append(key)
+        else:
+            lines.append("%s = %s\n" % (key, toml_value(value[key])))
+
+    result = "".join(lines)
+    for key in tables:
+        name = prefix + key
+        if result:
+            result += "\n"
+        result += "[%s]\n" % name + toml_table(value[key], name + ".")
+    return result
+
+
+def to_toml(value):
+    if not isinstance(value, dict):
+        return toml_value(value)
+    return toml_table(value, "")
+
+
+FILTERS = {
+    "snake_case": snake_case,
+    "camel_case": camel_case,
+    "pascal_case": pascal_case,
+    "kebab_case": kebab_case,
+    "screaming_snake": screaming_snake,
+    "title": title,
+    "slugify": slugify,
+    "go_package": go_package,
+    "go_exported": go_exported,
+    "pluralize": pluralize,
+    "singularize": singularize,
+    "quote_yaml": quote_yaml,
+    "to_json": to_json,
+    "to_yaml": to_yaml,
+    "to_toml": to_toml,
+}
+
+
+class ScaffoldFiltersExtension(Extension):
+    def __init__(self, environment):
+        super().__init__(environment)
+        environment.filters.update(FILTERS)
+
+
+class ScaffoldDelimitersExtension(Extension):
+    def __init__(self, environment):
+        super().__init__(environment)
+        # files keep their trailing newline (keep_trailing_newline: false drops it, see core/text.go)
+        environment.keep_trailing_newline = True
+        delimiters = environment.globals.get("_scaffold_delimiters") or {}
+        for name, value in delimiters.items():
+            if value:
+                setattr(environment, name, value)
@@ -0,0 +1,56 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestJinjaExtensionOptionsModule(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv("HOME", cacheDir)
+
+	if _, err := core.JinjaExtensionOptions(); err != nil {
+		t.Fatalf("JinjaExtensionOptions failed: %v", err)
+	}
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	moduleFiles, _ := filepath.Glob(filepath.Join(userCacheDir, "goscaffold", "*", "scaffold_extensions.py"))
+	if len(moduleFiles) != 1 {
+		t.Fatalf("extracted modules = %v, expected one under %s", moduleFiles, userCacheDir)
+	}
+	moduleFile := moduleFiles[0]
+
+	// The module folder is private to the user
+	info, err := os.Stat(filepath.Dir(moduleFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o700 {
+		t.Errorf("module folder mode = %o, expected 700", mode)
+	}
+
+	// A module that does not hold the embedded content is replaced rather than trusted
+	expected, err := os.ReadFile(moduleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(moduleFile, []byte("import os\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.JinjaExtensionOptions(); err != nil {
+		t.Fatalf("JinjaExtensionOptions failed: %v", err)
+	}
+	actual, err := os.ReadFile(moduleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != string(expected) {
+		t.Errorf("modified module was kept:\n%s", actual)
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+)
+
+// rgxPathLoop matches a loop declared at the start of a path segment
+// (e.g. "[[item in scaffold.handlers]]{{ item.name }}.go")
+var rgxPathLoop = regexp.MustCompile(`^\[\[\s*([A-Za-z_][A-Za-z0-9_]*)\s+in\s+(.+?)\s*\]\]`)
+
+// PathIteration is one rendering of a template path declaring loops
+type PathIteration struct {
+	// TemplatePath is the template path without its loop declarations
+	TemplatePath string
+	// Vars binds the loop variables to the elements of this iteration
+	Vars map[string]any
+}
+
+// HasPathLoop returns whether a template path declares a loop in one of its segments
+func HasPathLoop(templatePath string) bool {
+	for _, segment := range strings.Split(templatePath, "/") {
+		if rgxPathLoop.MatchString(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandPathLoops returns the renderings of a template path: one per element of the collection
+// of every loop it declares (loops of nested folders can use the variables of their parents)
+func ExpandPathLoops(templatePath string, engine Engine) ([]PathIteration, error) {
+	iterations := []PathIteration{{Vars: map[string]any{}}}
+	for i, segment := range strings.Split(templatePath, "/") {
+		match := rgxPathLoop.FindStringSubmatch(segment)
+		if match == nil {
+			for j := range iterations {
+				iterations[j].TemplatePath = joinSegment(iterations[j].TemplatePath, i, segment)
+			}
+			continue
+		}
+
+		variable, expression := match[1], match[2]
+		if variable == "scaffold" {
+			return nil, fmt.Errorf("loop variable of %s cannot be named scaffold", templatePath)
+		}
+
+		expanded := []PathIteration{}
+		for _, iteration := range iterations {
+			elements, err := engine.EvaluateList(expression, iteration.Vars)
+			if err != nil {
+				return nil, fmt.Errorf("loop of %s: %w", templatePath, err)
+			}
+
+			for _, element := range elements {
+				vars := maps.Clone(iteration.Vars)
+				vars[variable] = element
+				expanded = append(expanded, PathIteration{
+					TemplatePath: joinSegment(iteration.TemplatePath, i, segment[len(match[0]):]),
+					Vars:         vars,
+				})
+			}
+		}
+		iterations = expanded
+	}
+	return iterations, nil
+}
+
+func joinSegment(templatePath string, index int, segment string) string {
+	if index == 0 {
+		return segment
+	}
+	return templatePath + "/" + segment
+}
@@ -0,0 +1,103 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestExpandPathLoops(t *testing.T) {
+	params := map[string]any{
+		"name":  "svc",
+		"empty": []any{},
+		"handlers": []any{
+			map[string]any{"name": "users", "methods": []any{"get", "list"}},
+			map[string]any{"name": "orders", "methods": []any{"create"}},
+		},
+	}
+
+	testCases := []struct {
+		engine       string
+		templatePath string
+		// expected maps the loop-free template paths to the rendered paths
+		expected  []string
+		expectErr bool
+	}{
+		{
+			engine:       core.JinjaEngineName,
+			templatePath: "{{ scaffold.name }}/main.go",
+			expected:     []string{"svc/main.go"},
+		},
+		{
+			engine:       core.JinjaEngineName,
+			templatePath: "{{ scaffold.name }}/handlers/[[h in scaffold.handlers]]{{ h.name }}.go",
+			expected:     []string{"svc/handlers/users.go", "svc/handlers/orders.go"},
+		},
+		{
+			engine:       core.JinjaEngineName,
+			templatePath: "[[h in scaffold.handlers]]{{ h.name }}/[[m in h.methods]]{{ m }}_{{ h.name }}.go",
+			expected:     []string{"users/get_users.go", "users/list_users.go", "orders/create_orders.go"},
+		},
+		{
+			engine:       core.JinjaEngineName,
+			templatePath: "[[ item in scaffold.empty ]]{{ item }}.go",
+			expected:     []string{},
+		},
+		{
+			engine:       core.JinjaEngineName,
+			templatePath: "[[h in scaffold.handlrs]]{{ h.name }}.go",
+			expectErr:    true,
+		},
+		{
+			engine:       core.JinjaEngineName,
+			templatePath: "[[scaffold in scaffold.handlers]]x.go",
+			expectErr:    true,
+		},
+		{
+			engine:       core.GoTemplateEngineName,
+			templatePath: "handlers/[[h in .scaffold.handlers]]{{ .h.name }}.go",
+			expected:     []string{"handlers/users.go", "handlers/orders.go"},
+		},
+	}
+
+	engines := map[string]core.Engine{}
+	for _, name := range []string{core.JinjaEngineName, core.GoTemplateEngineName} {
+		engine, err := core.NewEngine(name, params, core.EngineOptions{Workers: 1})
+		if err != nil {
+			t.Fatalf("NewEngine(%q) failed: %v", name, err)
+		}
+		defer engine.Close()
+		engines[name] = engine
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.engine+"/"+tc.templatePath, func(t *testing.T) {
+			engine := engines[tc.engine]
+			iterations, err := core.ExpandPathLoops(tc.templatePath, engine)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("ExpandPathLoops(%q) error = %v, expected error %t", tc.templatePath, err, tc.expectErr)
+			}
+			if tc.expectErr {
+				return
+			}
+
+			actual := []string{}
+			for _, iteration := range iterations {
+				rendered, err := engine.RenderStringWith(iteration.TemplatePath, entity.Delimiters{}, iteration.Vars)
+				if err != nil {
+					t.Fatalf("RenderStringWith(%q) failed: %v", iteration.TemplatePath, err)
+				}
+				actual = append(actual, rendered)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("ExpandPathLoops(%q) rendered %v, expected %v", tc.templatePath, actual, tc.expected)
+			}
+		})
+	}
+
+	if core.HasPathLoop("svc/handlers/{{ h.name }}.go") || !core.HasPathLoop("svc/[[h in scaffold.handlers]]{{ h.name }}/x.go") {
+		t.Errorf("HasPathLoop did not detect loop declarations")
+	}
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/copito/goscaffold/entity"
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultManifestPath is where the manifest is written inside the generated project
+const DefaultManifestPath = ".scaffold/answers.yaml"
+
+// ManifestPath returns the slash separated path of the manifest inside the generated
+// project (empty when the template disables it)
+func ManifestPath(config entity.ManifestConfig) (string, error) {
+	if config.Disabled {
+		return "", nil
+	}
+
+	manifestPath := config.Path
+	if manifestPath == "" {
+		manifestPath = DefaultManifestPath
+	}
+	if !filepath.IsLocal(manifestPath) {
+		return "", fmt.Errorf("manifest path %s must be inside the generated project", manifestPath)
+	}
+	return filepath.ToSlash(filepath.Clean(manifestPath)), nil
+}
+
+// ManifestAnswers returns the answers that can be recorded (secret prompts are left out)
+func ManifestAnswers(prompt entity.Prompt, params map[string]any) map[string]any {
+	answers := map[string]any{}
+	for key, value := range params {
+		item, found := prompt.Items[key]
+		if found && (item.Secret || item.HideEntered) {
+			continue
+		}
+		answers[key] = value
+	}
+	return answers
+}
+
+// ProjectRoot returns the single top-level folder generated by the plan (the generated
+// project) or an empty string when the plan generates several top-level paths
+func ProjectRoot(plan map[string]entity.PlanEntry) string {
+	root := ""
+	for renderedPath := range plan {
+		topLevel, _, _ := strings.Cut(renderedPath, "/")
+		if root != "" && topLevel != root {
+			return ""
+		}
+		root = topLevel
+	}
+
+	if entry, found := plan[root]; !found || !entry.Info.IsDir() {
+		return ""
+	}
+	return root
+}
+
+// ManifestFiles hashes the generated files of the plan written to fs, along with the kept files
+// read from disk (paths are relative to the project root)
+func ManifestFiles(fs afero.Fs, plan map[string]entity.PlanEntry, outputBasePath string, projectRoot string) ([]entity.ManifestFile, error) {
+	files := []entity.ManifestFile{}
+	for _, entry := range plan {
+		if !entry.Info.Mode().IsRegular() {
+			continue
+		}
+
+		source := fs
+		if entry.Kept {
+			source = afero.NewOsFs()
+		}
+		hash, err := FileSHA256(source, filepath.Join(outputBasePath, filepath.FromSlash(entry.RenderedPath)))
+		if err != nil {
+			return nil, err
+		}
+
+		relativePath := entry.RenderedPath
+		if projectRoot != "" {
+			relativePath = strings.TrimPrefix(relativePath, projectRoot+"/")
+		}
+		files = append(files, entity.ManifestFile{Path: relativePath, SHA256: hash})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+// FileSHA256 returns the hex encoded SHA-256 hash of a file of fs
+func FileSHA256(fs afero.Fs, src string) (string, error) {
+	source, err := fs.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, source)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WriteManifest writes the manifest as YAML to fs (creating its folder)
+func WriteManifest(fs afero.Fs, dst string, manifest entity.Manifest) error {
+	err := fs.MkdirAll(path.Dir(filepath.ToSlash(dst)), os.FileMode(0o755))
+	if err != nil {
+		return err
+	}
+
+	data, err := ToYAML(manifest)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, dst, []byte(data), 0o644)
+}
+
+// ReadManifest reads a manifest written by WriteManifest
+func ReadManifest(src string) (entity.Manifest, error) {
+	manifest := entity.Manifest{}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return manifest, err
+	}
+
+	err = yaml.Unmarshal(data, &manifest)
+	return manifest, err
+}
+
+// RepositoryCommit returns the commit checked out by the git repository holding a
+// local path (empty when the path is not inside a git repository)
+func RepositoryCommit(localPath string) string {
+	repository, err := git.PlainOpenWithOptions(localPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
@@ -0,0 +1,126 @@
+package core_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/afero"
+)
+
+func TestManifestPath(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    entity.ManifestConfig
+		expected  string
+		expectErr bool
+	}{
+		{name: "default", expected: core.DefaultManifestPath},
+		{name: "custom", config: entity.ManifestConfig{Path: "./meta/scaffold.yaml"}, expected: "meta/scaffold.yaml"},
+		{name: "disabled", config: entity.ManifestConfig{Path: "meta.yaml", Disabled: true}, expected: ""},
+		{name: "outside project", config: entity.ManifestConfig{Path: "../answers.yaml"}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.ManifestPath(tc.config)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("ManifestPath(%+v) error = %v, expected error %t", tc.config, err, tc.expectErr)
+			}
+			if actual != tc.expected {
+				t.Errorf("ManifestPath(%+v) = %q, expected %q", tc.config, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestManifestAnswers(t *testing.T) {
+	prompt := entity.Prompt{Items: map[string]entity.PromptItem{
+		"name":     {},
+		"token":    {Secret: true},
+		"password": {HideEntered: true},
+	}}
+	params := map[string]any{"name": "svc", "token": "abc", "password": "hunter2", "_private": "x"}
+
+	actual := core.ManifestAnswers(prompt, params)
+	expected := map[string]any{"name": "svc", "_private": "x"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ManifestAnswers = %v, expected %v", actual, expected)
+	}
+}
+
+func TestManifestFiles(t *testing.T) {
+	outputBasePath := t.TempDir()
+	writeTestFiles(t, outputBasePath, map[string]string{
+		"svc/main.go":        "package main\n",
+		"svc/docs/README.md": "",
+	})
+
+	plan := map[string]entity.PlanEntry{}
+	for _, renderedPath := range []string{"svc", "svc/docs", "svc/main.go", "svc/docs/README.md"} {
+		info, err := os.Stat(filepath.Join(outputBasePath, renderedPath))
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		plan[renderedPath] = entity.PlanEntry{RenderedPath: renderedPath, Info: info}
+	}
+
+	projectRoot := core.ProjectRoot(plan)
+	if projectRoot != "svc" {
+		t.Fatalf("ProjectRoot = %q, expected %q", projectRoot, "svc")
+	}
+
+	actual, err := core.ManifestFiles(afero.NewOsFs(), plan, outputBasePath, projectRoot)
+	if err != nil {
+		t.Fatalf("ManifestFiles failed: %v", err)
+	}
+	mainHash := sha256.Sum256([]byte("package main\n"))
+	expected := []entity.ManifestFile{
+		{Path: "docs/README.md", SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{Path: "main.go", SHA256: hex.EncodeToString(mainHash[:])},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ManifestFiles = %v, expected %v", actual, expected)
+	}
+
+	manifest := entity.Manifest{
+		Template:        entity.ManifestTemplate{Source: "gh:acme/templates", Commit: "abc123"},
+		ScaffoldVersion: "0.0.3",
+		Answers:         map[string]any{"name": "svc"},
+		Files:           actual,
+	}
+	manifestPath := filepath.Join(outputBasePath, "svc", core.DefaultManifestPath)
+	if err := core.WriteManifest(afero.NewOsFs(), manifestPath, manifest); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	written, err := core.ReadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if !reflect.DeepEqual(written, manifest) {
+		t.Errorf("ReadManifest = %+v, expected %+v", written, manifest)
+	}
+
+	// Kept files are not generated, their content on disk is hashed
+	keptPlan := map[string]entity.PlanEntry{
+		"svc/main.go": {RenderedPath: "svc/main.go", Info: plan["svc/main.go"].Info, Kept: true},
+	}
+	kept, err := core.ManifestFiles(afero.NewMemMapFs(), keptPlan, outputBasePath, projectRoot)
+	if err != nil {
+		t.Fatalf("ManifestFiles of kept files failed: %v", err)
+	}
+	if !reflect.DeepEqual(kept, expected[1:]) {
+		t.Errorf("ManifestFiles of kept files = %v, expected %v", kept, expected[1:])
+	}
+
+	delete(plan, "svc")
+	plan["LICENSE"] = entity.PlanEntry{RenderedPath: "LICENSE", Info: plan["svc/main.go"].Info}
+	if root := core.ProjectRoot(plan); root != "" {
+		t.Errorf("ProjectRoot of several top-level paths = %q, expected none", root)
+	}
+}
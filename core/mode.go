@@ -0,0 +1,49 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/copito/goscaffold/entity"
+)
+
+// ParseFileMode parses octal permissions (e.g. "0755", "755" or "0o755")
+func ParseFileMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(strings.TrimPrefix(value, "0o"), 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid file mode %q (expected octal permissions like 0755)", value)
+	}
+	return os.FileMode(mode), nil
+}
+
+// OutputFileMode returns the permissions of a generated file/folder: the ones of the template
+// path without the umask bits, unless a file mode override matches the template path
+// (the last matching override wins)
+func OutputFileMode(config entity.TemplateConfig, templatePath string, sourceMode os.FileMode) (os.FileMode, error) {
+	mode := sourceMode.Perm()
+	if config.Umask != "" {
+		umask, err := ParseFileMode(config.Umask)
+		if err != nil {
+			return 0, fmt.Errorf("umask: %w", err)
+		}
+		mode &^= umask
+	}
+
+	for _, override := range config.FileModes {
+		matched, err := MatchAnyGlob([]string{override.Path}, templatePath)
+		if err != nil {
+			return 0, err
+		}
+		if !matched {
+			continue
+		}
+
+		mode, err = ParseFileMode(override.Mode)
+		if err != nil {
+			return 0, fmt.Errorf("file_modes %s: %w", override.Path, err)
+		}
+	}
+	return mode, nil
+}
@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestOutputFileMode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       entity.TemplateConfig
+		templatePath string
+		sourceMode   os.FileMode
+		expected     os.FileMode
+		expectErr    bool
+	}{
+		{
+			name:         "source permissions are kept",
+			templatePath: "project/scripts/build.sh",
+			sourceMode:   0o755,
+			expected:     0o755,
+		},
+		{
+			name:         "umask removes permission bits",
+			config:       entity.TemplateConfig{Umask: "027"},
+			templatePath: "project/scripts/build.sh",
+			sourceMode:   0o777,
+			expected:     0o750,
+		},
+		{
+			name: "matching override wins over source and umask",
+			config: entity.TemplateConfig{
+				Umask:     "022",
+				FileModes: []entity.FileMode{{Path: "*/scripts/*.sh", Mode: "0775"}},
+			},
+			templatePath: "project/scripts/build.sh",
+			sourceMode:   0o644,
+			expected:     0o775,
+		},
+		{
+			name: "last matching override wins",
+			config: entity.TemplateConfig{
+				FileModes: []entity.FileMode{{Path: "**.sh", Mode: "0755"}, {Path: "*/secret.sh", Mode: "0o700"}},
+			},
+			templatePath: "project/secret.sh",
+			sourceMode:   0o644,
+			expected:     0o700,
+		},
+		{
+			name:         "non matching override is ignored",
+			config:       entity.TemplateConfig{FileModes: []entity.FileMode{{Path: "**.sh", Mode: "0755"}}},
+			templatePath: "project/main.go",
+			sourceMode:   0o644,
+			expected:     0o644,
+		},
+		{
+			name:         "invalid umask",
+			config:       entity.TemplateConfig{Umask: "abc"},
+			templatePath: "project/main.go",
+			sourceMode:   0o644,
+			expectErr:    true,
+		},
+		{
+			name:         "invalid override mode",
+			config:       entity.TemplateConfig{FileModes: []entity.FileMode{{Path: "**", Mode: "1777"}}},
+			templatePath: "project/main.go",
+			sourceMode:   0o644,
+			expectErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.OutputFileMode(tc.config, tc.templatePath, tc.sourceMode)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("OutputFileMode(%q) error = %v, expected error %t", tc.templatePath, err, tc.expectErr)
+			}
+			if actual != tc.expected {
+				t.Errorf("OutputFileMode(%q) = %o, expected %o", tc.templatePath, actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// DefaultWorkers is the number of files rendered concurrently when not configured
+func DefaultWorkers() int {
+	return min(runtime.NumCPU(), 8)
+}
+
+// ParallelFor calls fn for every index in [0, count) using at most workers goroutines,
+// returning the errors of every failed call (in index order)
+func ParallelFor(workers int, count int, fn func(i int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, count)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, count) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+
+	for i := range count {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package core_test
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestParallelFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		workers int
+		count   int
+		failing map[int]bool
+	}{
+		{name: "sequential", workers: 1, count: 10},
+		{name: "more items than workers", workers: 4, count: 100},
+		{name: "more workers than items", workers: 8, count: 3},
+		{name: "no items", workers: 4, count: 0},
+		{name: "invalid worker count", workers: 0, count: 5},
+		{name: "every error is reported", workers: 3, count: 20, failing: map[int]bool{2: true, 7: true, 19: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			seen := make([]atomic.Bool, tt.count)
+			err := core.ParallelFor(tt.workers, tt.count, func(i int) error {
+				calls.Add(1)
+				seen[i].Store(true)
+				if tt.failing[i] {
+					return fmt.Errorf("item %d failed", i)
+				}
+				return nil
+			})
+
+			if calls.Load() != int64(tt.count) {
+				t.Errorf("ParallelFor called fn %d times, want %d", calls.Load(), tt.count)
+			}
+			for i := range seen {
+				if !seen[i].Load() {
+					t.Errorf("ParallelFor never called fn(%d)", i)
+				}
+			}
+
+			if len(tt.failing) == 0 {
+				if err != nil {
+					t.Errorf("ParallelFor returned unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ParallelFor returned no error, want %d errors", len(tt.failing))
+			}
+			for i := range tt.failing {
+				want := fmt.Sprintf("item %d failed", i)
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("ParallelFor error %q does not report %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
-	"github.com/kluctl/go-jinja2"
+	"github.com/copito/goscaffold/entity"
+	"github.com/spf13/afero"
 )
 
+// TemplateConfigFileName is the configuration file a template can ship at its root
+const TemplateConfigFileName = "scaffold.yaml"
+
 func FileNameWithoutExtension(fileName string) string {
 	if pos := strings.LastIndexByte(fileName, '.'); pos != -1 {
 		return fileName[:pos]
@@ -29,8 +34,8 @@ func PathExists(path string) (bool, error) {
 	return false, err
 }
 
-// PathCopy copies file/folder to another location
-func PathCopy(src, dst string) (int64, error) {
+// PathCopy copies file/folder to another location of fs (keeping its permissions)
+func PathCopy(fs afero.Fs, src, dst string) (int64, error) {
 	sourceFileStat, err := os.Stat(src)
 	if err != nil {
 		return 0, err
@@ -46,43 +51,142 @@ func PathCopy(src, dst string) (int64, error) {
 	}
 	defer source.Close()
 
-	destination, err := os.Create(dst)
+	destination, err := fs.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, sourceFileStat.Mode().Perm())
 	if err != nil {
 		return 0, err
 	}
 	defer destination.Close()
 	nBytes, err := io.Copy(destination, source)
-	return nBytes, err
+	if err != nil {
+		return nBytes, err
+	}
+
+	// The process umask applies on creation (and existing files keep their mode)
+	return nBytes, fs.Chmod(dst, sourceFileStat.Mode().Perm())
 }
 
-// RenderFileContent renders a file using the jinja templated engine
-func RenderFileContent(src string, jj *jinja2.Jinja2) error {
+// RenderFileContent renders a file using the template engine and returns its content
+// (the file itself is left untouched)
+func RenderFileContent(src string, engine Engine, delimiters entity.Delimiters, text entity.TextOptions) ([]byte, error) {
 	sourceFileStat, err := os.Stat(src)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !sourceFileStat.Mode().IsRegular() {
-		return fmt.Errorf("%s is not a regular file", src)
+		return nil, fmt.Errorf("%s is not a regular file", src)
 	}
 
 	data, err := os.ReadFile(src)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(data) == 0 {
-		return nil
+		return data, nil
 	}
 
-	dataString := string(data)
-	renderedString, err := jj.RenderString(dataString)
+	dataString, textSource, err := DecodeText(data, text)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	os.WriteFile(src, []byte(renderedString), sourceFileStat.Mode().Perm())
-	return nil
+	renderedString, err := engine.RenderString(dataString, delimiters)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodeText(renderedString, textSource, text)
+}
+
+// GenerateFile writes a template file of the plan to its output path of fs (rendering its content
+// in memory unless copied without render) so the output is written exactly once
+func GenerateFile(fs afero.Fs, entry entity.PlanEntry, dst string, engine Engine) (int64, error) {
+	// Binary files (and verbatim files without text options) are copied byte-for-byte
+	if isVerbatimCopy(entry) {
+		nBytes, err := PathCopy(fs, entry.SourcePath, dst)
+		if err != nil {
+			return nBytes, err
+		}
+		return nBytes, fs.Chmod(dst, entry.Mode)
+	}
+
+	data, err := RenderFile(entry, engine)
+	if err != nil {
+		return 0, err
+	}
+
+	err = afero.WriteFile(fs, dst, data, entry.Mode)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(data)), fs.Chmod(dst, entry.Mode)
+}
+
+// RenderFile returns the content generated for a template file of the plan
+func RenderFile(entry entity.PlanEntry, engine Engine) ([]byte, error) {
+	src := entry.SourcePath
+	sourceFileStat, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sourceFileStat.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", src)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if isVerbatimCopy(entry) {
+		return data, nil
+	}
+
+	textOptions := entry.Text
+	if entry.CopyWithoutRender {
+		textOptions = copiedTextOptions(textOptions)
+	}
+
+	text, textSource, err := DecodeText(data, textOptions)
+	if err != nil {
+		return nil, WithTemplateFile(err, entry.TemplatePath)
+	}
+
+	if !entry.CopyWithoutRender && len(text) > 0 {
+		text, err = engine.RenderStringWith(text, entry.Delimiters, entry.Vars)
+		if err != nil {
+			return nil, WithTemplateFile(err, entry.TemplatePath)
+		}
+	}
+
+	if entry.Formatter != "" {
+		formatted, err := FormatContent(entry.Formatter, []byte(text))
+		if err != nil {
+			return nil, WithTemplateFile(err, entry.TemplatePath)
+		}
+		text = string(formatted)
+	}
+
+	data, err = EncodeText(text, textSource, textOptions)
+	if err != nil {
+		return nil, WithTemplateFile(err, entry.TemplatePath)
+	}
+	return data, nil
+}
+
+// isVerbatimCopy returns whether a file of the plan is generated byte-for-byte
+// (binary files and files copied without render nor text options)
+func isVerbatimCopy(entry entity.PlanEntry) bool {
+	return entry.CopyWithoutRender && (entry.Binary || copiedTextOptions(entry.Text) == (entity.TextOptions{}))
+}
+
+// copiedTextOptions returns the text options applying to files copied without render
+// (the trailing newline is only dropped from rendered content)
+func copiedTextOptions(options entity.TextOptions) entity.TextOptions {
+	options.KeepTrailingNewline = nil
+	return options
 }
 
 // Get delta relative path
@@ -106,3 +210,148 @@ func DeltaRelativePath(basePath string, currentPath string) string {
 	}
 	return relPath
 }
+
+// TemplateRootPath narrows the template source down to one of its subdirectories
+// (e.g. templates/go-service inside a monorepo of templates)
+func TemplateRootPath(sourcePath string, directory string) (string, error) {
+	if directory == "" {
+		return sourcePath, nil
+	}
+
+	cleanDirectory := filepath.Clean(directory)
+	if filepath.IsAbs(cleanDirectory) {
+		return "", fmt.Errorf("directory %s must be relative to the template source", directory)
+	}
+	if cleanDirectory == ".." || strings.HasPrefix(cleanDirectory, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("directory %s escapes the template source", directory)
+	}
+
+	rootPath := filepath.Join(sourcePath, cleanDirectory)
+	rootStat, err := os.Stat(rootPath)
+	if err != nil {
+		return "", err
+	}
+
+	if !rootStat.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", rootPath)
+	}
+
+	return rootPath, nil
+}
+
+// CheckSymlinkTarget verifies a symlink generated at linkPath (slash separated, relative to
+// the output folder) pointing to target stays inside the output folder
+func CheckSymlinkTarget(linkPath string, target string) error {
+	if target == "" {
+		return fmt.Errorf("symlink %s has an empty target", linkPath)
+	}
+
+	if path.IsAbs(target) || filepath.IsAbs(target) {
+		return fmt.Errorf("symlink %s has an absolute target %s", linkPath, target)
+	}
+
+	resolved := path.Join(path.Dir(linkPath), filepath.ToSlash(target))
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return fmt.Errorf("symlink %s target %s escapes the output folder", linkPath, target)
+	}
+	return nil
+}
+
+// HasEmptyPathSegment returns whether a rendered slash separated path has an empty
+// segment (e.g. when rendering "{% if scaffold.use_docker %}docker{% endif %}/Dockerfile")
+func HasEmptyPathSegment(renderedPath string) bool {
+	for _, segment := range strings.Split(renderedPath, "/") {
+		if strings.TrimSpace(segment) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// reservedPathCharacters can not be used in generated file names on every platform
+// (backslashes would be separators on Windows, use "/" in template paths instead)
+const reservedPathCharacters = `<>:"|?*\`
+
+// CleanRenderedPath validates a rendered slash separated path (relative to the output folder)
+// and returns it cleaned, rejecting absolute paths, paths escaping the output folder and
+// names with NUL, control or reserved characters (e.g. answers like "../../etc")
+func CleanRenderedPath(renderedPath string) (string, error) {
+	for _, r := range renderedPath {
+		if r == 0 {
+			return "", fmt.Errorf("rendered path %q contains a NUL character", renderedPath)
+		}
+		if r < 0x20 || r == 0x7f {
+			return "", fmt.Errorf("rendered path %q contains a control character", renderedPath)
+		}
+		if strings.ContainsRune(reservedPathCharacters, r) {
+			return "", fmt.Errorf("rendered path %q contains the reserved character %q", renderedPath, r)
+		}
+	}
+
+	if path.IsAbs(renderedPath) {
+		return "", fmt.Errorf("rendered path %q is absolute", renderedPath)
+	}
+
+	cleanPath := path.Clean(renderedPath)
+	if cleanPath == "." {
+		return "", fmt.Errorf("rendered path %q is the output folder itself", renderedPath)
+	}
+	if cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return "", fmt.Errorf("rendered path %q escapes the output folder", renderedPath)
+	}
+	return cleanPath, nil
+}
+
+// RenderedOutputPath validates the rendered path of a template path and returns it cleaned. Paths
+// rendered with an empty segment (e.g. "{% if scaffold.use_docker %}docker{% endif %}/Dockerfile") are
+// skipped, unless answers added separators to them (e.g. "/tmp/evil" making the path absolute)
+func RenderedOutputPath(templatePath string, renderedPath string) (string, bool, error) {
+	// Template file names can not hold separators, every extra segment comes from the answers
+	addsSegments := strings.Count(renderedPath, "/") > strings.Count(templatePath, "/")
+	if HasEmptyPathSegment(renderedPath) && !addsSegments {
+		return renderedPath, true, nil
+	}
+
+	cleanPath, err := CleanRenderedPath(renderedPath)
+	return cleanPath, false, err
+}
+
+// IsSubPath returns whether child is parent itself or one of its descendants
+// (symlinks are resolved for the paths that exist)
+func IsSubPath(parent string, child string) (bool, error) {
+	resolvedParent, err := resolvePath(parent)
+	if err != nil {
+		return false, err
+	}
+	resolvedChild, err := resolvePath(child)
+	if err != nil {
+		return false, err
+	}
+
+	relPath, err := filepath.Rel(resolvedParent, resolvedChild)
+	if err != nil {
+		return false, nil
+	}
+	return filepath.IsLocal(relPath) || relPath == ".", nil
+}
+
+// resolvePath returns the absolute path of a file with the symlinks of its existing
+// ancestors resolved (the file itself may not exist yet)
+func resolvePath(filePath string) (string, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	missingPath := ""
+	for currentPath := absPath; ; currentPath = filepath.Dir(currentPath) {
+		resolvedPath, err := filepath.EvalSymlinks(currentPath)
+		if err == nil {
+			return filepath.Join(resolvedPath, missingPath), nil
+		}
+		if filepath.Dir(currentPath) == currentPath {
+			return absPath, nil
+		}
+		missingPath = filepath.Join(filepath.Base(currentPath), missingPath)
+	}
+}
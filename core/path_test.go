@@ -1,9 +1,12 @@
 package core_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
 )
 
 func TestDeltaRelativePath(t *testing.T) {
@@ -58,3 +61,290 @@ func TestDeltaRelativePath(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateRootPath(t *testing.T) {
+	sourcePath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sourcePath, "templates", "go-service"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourcePath, "templates", "README.md"), []byte("docs"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		directory    string
+		expectedPath string
+		expectError  bool
+	}{
+		{directory: "", expectedPath: sourcePath},
+		{directory: "templates/go-service", expectedPath: filepath.Join(sourcePath, "templates", "go-service")},
+		{directory: "templates/go-service/", expectedPath: filepath.Join(sourcePath, "templates", "go-service")},
+		{directory: "templates/go-lib", expectError: true},
+		{directory: "templates/README.md", expectError: true},
+		{directory: "../templates", expectError: true},
+		{directory: "/templates", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.directory, func(t *testing.T) {
+			actual, err := core.TemplateRootPath(sourcePath, tc.directory)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("TemplateRootPath(%q) = %q, expected an error", tc.directory, actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TemplateRootPath(%q) failed: %v", tc.directory, err)
+			}
+			if actual != tc.expectedPath {
+				t.Errorf("TemplateRootPath(%q) = %q, expected %q", tc.directory, actual, tc.expectedPath)
+			}
+		})
+	}
+}
+
+func TestHasEmptyPathSegment(t *testing.T) {
+	testCases := []struct {
+		renderedPath string
+		expected     bool
+	}{
+		{renderedPath: "project/docker/Dockerfile", expected: false},
+		{renderedPath: "project//Dockerfile", expected: true},
+		{renderedPath: "project/ /Dockerfile", expected: true},
+		{renderedPath: "/Dockerfile", expected: true},
+		{renderedPath: "project/", expected: true},
+		{renderedPath: "", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.renderedPath, func(t *testing.T) {
+			actual := core.HasEmptyPathSegment(tc.renderedPath)
+			if actual != tc.expected {
+				t.Errorf("HasEmptyPathSegment(%q) = %t, expected %t", tc.renderedPath, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCheckSymlinkTarget(t *testing.T) {
+	testCases := []struct {
+		linkPath  string
+		target    string
+		expectErr bool
+	}{
+		{linkPath: "project/current", target: "v1", expectErr: false},
+		{linkPath: "project/bin/tool", target: "../scripts/tool.sh", expectErr: false},
+		{linkPath: "project/link", target: "..", expectErr: false},
+		{linkPath: "project/link", target: "../..", expectErr: true},
+		{linkPath: "project/link", target: "../../etc/passwd", expectErr: true},
+		{linkPath: "link", target: "../outside", expectErr: true},
+		{linkPath: "project/link", target: "/etc/passwd", expectErr: true},
+		{linkPath: "project/link", target: "", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.linkPath+"->"+tc.target, func(t *testing.T) {
+			err := core.CheckSymlinkTarget(tc.linkPath, tc.target)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("CheckSymlinkTarget(%q, %q) error = %v, expected error %t", tc.linkPath, tc.target, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestCleanRenderedPath(t *testing.T) {
+	testCases := []struct {
+		renderedPath string
+		expected     string
+		expectErr    bool
+	}{
+		{renderedPath: "project/cmd/main.go", expected: "project/cmd/main.go"},
+		{renderedPath: "project/./cmd/../main.go", expected: "project/main.go"},
+		{renderedPath: "project/docs/", expected: "project/docs"},
+		{renderedPath: "../../etc/passwd", expectErr: true},
+		{renderedPath: "project/../../outside", expectErr: true},
+		{renderedPath: "project/..", expectErr: true},
+		{renderedPath: "/etc/passwd", expectErr: true},
+		{renderedPath: "C:/Windows/system.ini", expectErr: true},
+		{renderedPath: "project\\..\\..\\outside", expectErr: true},
+		{renderedPath: "project/na\x00me.go", expectErr: true},
+		{renderedPath: "project/na\nme.go", expectErr: true},
+		{renderedPath: "project/what?.go", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.renderedPath, func(t *testing.T) {
+			actual, err := core.CleanRenderedPath(tc.renderedPath)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("CleanRenderedPath(%q) error = %v, expected error %t", tc.renderedPath, err, tc.expectErr)
+			}
+			if actual != tc.expected {
+				t.Errorf("CleanRenderedPath(%q) = %q, expected %q", tc.renderedPath, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsSubPath(t *testing.T) {
+	root := t.TempDir()
+	templatePath := filepath.Join(root, "template")
+	err := os.MkdirAll(filepath.Join(templatePath, "project"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink(templatePath, filepath.Join(root, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		child    string
+		expected bool
+	}{
+		{name: "same folder", child: templatePath, expected: true},
+		{name: "existing subfolder", child: filepath.Join(templatePath, "project"), expected: true},
+		{name: "missing subfolder", child: filepath.Join(templatePath, "output", "nested"), expected: true},
+		{name: "through symlink", child: filepath.Join(root, "link", "output"), expected: true},
+		{name: "sibling", child: filepath.Join(root, "output"), expected: false},
+		{name: "parent", child: root, expected: false},
+		{name: "name prefix", child: templatePath + "-output", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := core.IsSubPath(templatePath, tc.child)
+			if err != nil {
+				t.Fatalf("IsSubPath(%q) failed: %v", tc.child, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("IsSubPath(%q) = %t, expected %t", tc.child, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRenderedOutputPath(t *testing.T) {
+	testCases := []struct {
+		templatePath string
+		renderedPath string
+		expected     string
+		skipped      bool
+		expectErr    bool
+	}{
+		{templatePath: "{{ scaffold.name }}/a.txt", renderedPath: "svc/a.txt", expected: "svc/a.txt"},
+		{templatePath: "{{ scaffold.name }}/a.txt", renderedPath: "acme/svc/a.txt", expected: "acme/svc/a.txt"},
+		{templatePath: "{% if scaffold.docker %}docker{% endif %}/Dockerfile", renderedPath: "/Dockerfile", expected: "/Dockerfile", skipped: true},
+		{templatePath: "svc/{{ scaffold.dir }}/a.txt", renderedPath: "svc//a.txt", expected: "svc//a.txt", skipped: true},
+		{templatePath: "{{ scaffold.name }}/a.txt", renderedPath: "/tmp/evil/a.txt", expectErr: true},
+		{templatePath: "{{ scaffold.name }}", renderedPath: "/tmp/evil", expectErr: true},
+		{templatePath: "svc/{{ scaffold.name }}/a.txt", renderedPath: "svc//tmp/a.txt", expected: "svc/tmp/a.txt"},
+		{templatePath: "{{ scaffold.name }}/a.txt", renderedPath: "\\tmp\\evil/a.txt", expectErr: true},
+		{templatePath: "{{ scaffold.name }}/a.txt", renderedPath: "../../evil/a.txt", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.renderedPath, func(t *testing.T) {
+			actual, skipped, err := core.RenderedOutputPath(tc.templatePath, tc.renderedPath)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("RenderedOutputPath(%q, %q) error = %v, expected error %t", tc.templatePath, tc.renderedPath, err, tc.expectErr)
+			}
+			if tc.expectErr {
+				return
+			}
+			if actual != tc.expected || skipped != tc.skipped {
+				t.Errorf("RenderedOutputPath(%q, %q) = %q, %t, expected %q, %t", tc.templatePath, tc.renderedPath, actual, skipped, tc.expected, tc.skipped)
+			}
+		})
+	}
+}
+
+func TestRenderFileContent(t *testing.T) {
+	hookPath := filepath.Join(t.TempDir(), "pre_gen_project.go")
+	source := "// {{ .scaffold.name }}\n"
+	err := os.WriteFile(hookPath, []byte(source), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := core.NewEngine(core.GoTemplateEngineName, map[string]any{"name": "svc"}, core.EngineOptions{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+	defer engine.Close()
+
+	actual, err := core.RenderFileContent(hookPath, engine, entity.Delimiters{}, entity.TextOptions{})
+	if err != nil {
+		t.Fatalf("RenderFileContent failed: %v", err)
+	}
+	if string(actual) != "// svc\n" {
+		t.Errorf("RenderFileContent = %q, expected %q", actual, "// svc\n")
+	}
+
+	unchanged, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(unchanged) != source {
+		t.Errorf("RenderFileContent modified its source file: %q", unchanged)
+	}
+}
+
+func TestRenderFileTrailingNewline(t *testing.T) {
+	drop := false
+	sourcePath := filepath.Join(t.TempDir(), "deploy.yaml")
+	err := os.WriteFile(sourcePath, []byte("name: {{ .scaffold.name }}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := core.NewEngine(core.GoTemplateEngineName, map[string]any{"name": "svc"}, core.EngineOptions{Workers: 1})
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+	defer engine.Close()
+
+	testCases := []struct {
+		name              string
+		copyWithoutRender bool
+		text              entity.TextOptions
+		expected          string
+	}{
+		{
+			name:     "rendered",
+			text:     entity.TextOptions{KeepTrailingNewline: &drop},
+			expected: "name: svc",
+		},
+		{
+			name:              "copied without render",
+			copyWithoutRender: true,
+			text:              entity.TextOptions{KeepTrailingNewline: &drop},
+			expected:          "name: {{ .scaffold.name }}\n",
+		},
+		{
+			name:              "copied without render with newlines",
+			copyWithoutRender: true,
+			text:              entity.TextOptions{KeepTrailingNewline: &drop, Newline: core.NewlineCRLF},
+			expected:          "name: {{ .scaffold.name }}\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := entity.PlanEntry{
+				TemplatePath:      "deploy.yaml",
+				SourcePath:        sourcePath,
+				CopyWithoutRender: tc.copyWithoutRender,
+				Text:              tc.text,
+			}
+			actual, err := core.RenderFile(entry, engine)
+			if err != nil {
+				t.Fatalf("RenderFile failed: %v", err)
+			}
+			if string(actual) != tc.expected {
+				t.Errorf("RenderFile = %q, expected %q", actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/copito/goscaffold/entity"
+)
+
+// SortedPlan returns the plan entries ordered by rendered path (folders before their content)
+func SortedPlan(plan map[string]entity.PlanEntry) []entity.PlanEntry {
+	renderedPaths := make([]string, 0, len(plan))
+	for renderedPath := range plan {
+		renderedPaths = append(renderedPaths, renderedPath)
+	}
+	sort.Strings(renderedPaths)
+
+	entries := make([]entity.PlanEntry, 0, len(plan))
+	for _, renderedPath := range renderedPaths {
+		entries = append(entries, plan[renderedPath])
+	}
+	return entries
+}
+
+// DeletePlanEntries removes the entries matching any of the glob patterns (folders with their content)
+func DeletePlanEntries(plan map[string]entity.PlanEntry, patterns []string) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	for renderedPath := range plan {
+		matched, err := MatchAnyGlobOrParent(patterns, renderedPath)
+		if err != nil {
+			return err
+		}
+		if matched {
+			delete(plan, renderedPath)
+		}
+	}
+	return nil
+}
+
+// CheckPathCollisions verifies the entries of a template layer render to distinct output paths
+// (folders rendered to the same path are merged, files or symlinks would overwrite each other)
+func CheckPathCollisions(entries []entity.PlanEntry) error {
+	templatePaths := map[string][]string{}
+	isCollision := map[string]bool{}
+	renderedPaths := []string{}
+	for _, entry := range entries {
+		if _, ok := templatePaths[entry.RenderedPath]; !ok {
+			renderedPaths = append(renderedPaths, entry.RenderedPath)
+		}
+		templatePaths[entry.RenderedPath] = append(templatePaths[entry.RenderedPath], entry.TemplatePath)
+		if len(templatePaths[entry.RenderedPath]) > 1 && !entry.Info.IsDir() {
+			isCollision[entry.RenderedPath] = true
+		}
+	}
+
+	errs := []error{}
+	for _, renderedPath := range renderedPaths {
+		if !isCollision[renderedPath] {
+			continue
+		}
+		// Every template path of the collision, once (loop iterations share their template path)
+		collidingPaths := []string{}
+		for _, templatePath := range templatePaths[renderedPath] {
+			if !slices.Contains(collidingPaths, templatePath) {
+				collidingPaths = append(collidingPaths, templatePath)
+			}
+		}
+		if len(collidingPaths) == 1 {
+			errs = append(errs, fmt.Errorf("loop iterations of %s render to the same output path %s", collidingPaths[0], renderedPath))
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s render to the same output path %s", strings.Join(collidingPaths, ", "), renderedPath))
+	}
+	return errors.Join(errs...)
+}
+
+// CheckPlanParents verifies every entry of the plan is generated inside folders
+// (e.g. an extending template can not replace a folder of its base template with a file)
+func CheckPlanParents(plan map[string]entity.PlanEntry) error {
+	errs := []error{}
+	for _, entry := range SortedPlan(plan) {
+		for parent := path.Dir(entry.RenderedPath); parent != "."; parent = path.Dir(parent) {
+			parentEntry, ok := plan[parent]
+			if ok && !parentEntry.Info.IsDir() {
+				errs = append(errs, fmt.Errorf("%s (from %s) is generated inside %s (from %s) which is not a folder", entry.RenderedPath, entry.TemplatePath, parent, parentEntry.TemplatePath))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,119 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestDeletePlanEntries(t *testing.T) {
+	plan := map[string]entity.PlanEntry{}
+	for _, renderedPath := range []string{"svc", "svc/main.go", "svc/cmd", "svc/cmd/worker.go", "svc/cmd.md", "svc/README.md"} {
+		plan[renderedPath] = entity.PlanEntry{RenderedPath: renderedPath}
+	}
+
+	err := core.DeletePlanEntries(plan, []string{"svc/cmd", "**/*.md"})
+	if err != nil {
+		t.Fatalf("DeletePlanEntries failed: %v", err)
+	}
+
+	expectedPaths := []string{"svc", "svc/main.go"}
+	entries := core.SortedPlan(plan)
+	if len(entries) != len(expectedPaths) {
+		t.Fatalf("DeletePlanEntries kept %d entries, expected %d", len(entries), len(expectedPaths))
+	}
+	for i, entry := range entries {
+		if entry.RenderedPath != expectedPaths[i] {
+			t.Errorf("entry #%d = %q, expected %q", i, entry.RenderedPath, expectedPaths[i])
+		}
+	}
+}
+
+func TestCheckPathCollisions(t *testing.T) {
+	root := t.TempDir()
+	folderInfo, err := os.Lstat(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "file"), nil, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileInfo, err := os.Lstat(filepath.Join(root, "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name      string
+		entries   []entity.PlanEntry
+		expectErr string
+	}{
+		{
+			name: "distinct paths",
+			entries: []entity.PlanEntry{
+				{TemplatePath: "{{ scaffold.name }}", RenderedPath: "svc", Info: folderInfo},
+				{TemplatePath: "{{ scaffold.name }}/main.go", RenderedPath: "svc/main.go", Info: fileInfo},
+			},
+		},
+		{
+			name: "folders are merged",
+			entries: []entity.PlanEntry{
+				{TemplatePath: "{{ scaffold.name }}", RenderedPath: "svc", Info: folderInfo},
+				{TemplatePath: "{{ scaffold.alias }}", RenderedPath: "svc", Info: folderInfo},
+			},
+		},
+		{
+			name: "files rendered to the same path",
+			entries: []entity.PlanEntry{
+				{TemplatePath: "svc/{{ scaffold.a }}.go", RenderedPath: "svc/x.go", Info: fileInfo},
+				{TemplatePath: "svc/{{ scaffold.b }}.go", RenderedPath: "svc/x.go", Info: fileInfo},
+			},
+			expectErr: "svc/{{ scaffold.a }}.go, svc/{{ scaffold.b }}.go render to the same output path svc/x.go",
+		},
+		{
+			name: "loop iterations rendered to the same path",
+			entries: []entity.PlanEntry{
+				{TemplatePath: "[[h in scaffold.handlers]]{{ h.kind }}.go", RenderedPath: "http.go", Info: fileInfo},
+				{TemplatePath: "[[h in scaffold.handlers]]{{ h.kind }}.go", RenderedPath: "http.go", Info: fileInfo},
+			},
+			expectErr: "loop iterations of [[h in scaffold.handlers]]{{ h.kind }}.go render to the same output path http.go",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := core.CheckPathCollisions(tc.entries)
+			if tc.expectErr == "" {
+				if err != nil {
+					t.Errorf("CheckPathCollisions failed: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectErr {
+				t.Errorf("CheckPathCollisions error = %v, expected %q", err, tc.expectErr)
+			}
+		})
+	}
+
+	plan := map[string]entity.PlanEntry{
+		"svc":         {TemplatePath: "svc", RenderedPath: "svc", Info: folderInfo},
+		"svc/cmd":     {TemplatePath: "cmd.j2", RenderedPath: "svc/cmd", Info: fileInfo},
+		"svc/cmd/x":   {TemplatePath: "svc/cmd/x", RenderedPath: "svc/cmd/x", Info: fileInfo},
+		"svc/main.go": {TemplatePath: "svc/main.go", RenderedPath: "svc/main.go", Info: fileInfo},
+	}
+	err = core.CheckPlanParents(plan)
+	if err == nil || !strings.Contains(err.Error(), "svc/cmd/x (from svc/cmd/x) is generated inside svc/cmd (from cmd.j2)") {
+		t.Errorf("CheckPlanParents error = %v, expected a file generated inside a file", err)
+	}
+
+	delete(plan, "svc/cmd/x")
+	err = core.CheckPlanParents(plan)
+	if err != nil {
+		t.Errorf("CheckPlanParents failed: %v", err)
+	}
+}
@@ -3,7 +3,6 @@ package core
 import (
 	"errors"
 	"log/slog"
-	"os"
 	"regexp"
 	"strconv"
 
@@ -31,7 +30,7 @@ func NumberPrompt(logger *slog.Logger, label string, defaultValue string) string
 	result, err := prompt.Run()
 	if err != nil {
 		logger.Error("Prompt failed", "err", err)
-		os.Exit(1)
+		Exit(1)
 		return ""
 	}
 
@@ -56,7 +55,7 @@ func StringPrompt(logger *slog.Logger, label string, defaultValue string) string
 	result, err := prompt.Run()
 	if err != nil {
 		logger.Error("Prompt failed", "err", err)
-		os.Exit(1)
+		Exit(1)
 		return ""
 	}
 
@@ -84,7 +83,7 @@ func PasswordPrompt(logger *slog.Logger, label string, defaultValue string) stri
 	result, err := prompt.Run()
 	if err != nil {
 		logger.Error("Prompt failed", "err", err)
-		os.Exit(1)
+		Exit(1)
 		return ""
 	}
 
@@ -117,7 +116,7 @@ func BoolPrompt(logger *slog.Logger, label string, defaultValue string, isOpen b
 		result, err := prompt.Run()
 		if err != nil {
 			logger.Error("Prompt failed", "err", err)
-			os.Exit(1)
+			Exit(1)
 			return "FALSE"
 		}
 
@@ -142,7 +141,7 @@ func BoolPrompt(logger *slog.Logger, label string, defaultValue string, isOpen b
 	_, result, err := prompt.Run()
 	if err != nil {
 		logger.Error("Prompt failed", "err", err)
-		os.Exit(1)
+		Exit(1)
 		return ""
 	}
 
@@ -160,7 +159,7 @@ func SingleSelectPrompt(logger *slog.Logger, label string, items []string) strin
 	_, result, err := prompt.Run()
 	if err != nil {
 		logger.Error("Prompt failed", "err", err)
-		os.Exit(1)
+		Exit(1)
 		return ""
 	}
 
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/copito/goscaffold/entity"
+)
+
+const (
+	// RenderModeAll renders every (non binary) file of the template
+	RenderModeAll = "all"
+	// RenderModeSuffix only renders files marked with a template suffix (main.go.j2 -> main.go)
+	RenderModeSuffix = "suffix"
+)
+
+// TemplateSuffixes mark the files rendered when using the suffix render mode
+var TemplateSuffixes = []string{".j2", ".jinja"}
+
+// TrimTemplateSuffix removes the template suffix of a file name, returning whether it had one
+func TrimTemplateSuffix(name string) (string, bool) {
+	for _, suffix := range TemplateSuffixes {
+		trimmed, found := strings.CutSuffix(name, suffix)
+		if found && trimmed != "" && !strings.HasSuffix(trimmed, "/") {
+			return trimmed, true
+		}
+	}
+	return name, false
+}
+
+// CheckRenderModes verifies every layer of the template uses a known render mode
+func CheckRenderModes(layers []entity.TemplateLayer) error {
+	for _, layer := range layers {
+		switch layer.Config.RenderMode {
+		case "", RenderModeAll, RenderModeSuffix:
+		default:
+			return fmt.Errorf("template %s uses unknown render_mode %s (expected %s or %s)", layer.RootPath, layer.Config.RenderMode, RenderModeAll, RenderModeSuffix)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestTrimTemplateSuffix(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expected       string
+		expectedMarked bool
+	}{
+		{name: "project/main.go.j2", expected: "project/main.go", expectedMarked: true},
+		{name: "project/README.md.jinja", expected: "project/README.md", expectedMarked: true},
+		{name: "project/Makefile.j2", expected: "project/Makefile", expectedMarked: true},
+		{name: "project/main.go", expected: "project/main.go", expectedMarked: false},
+		{name: "project/config.j2.yaml", expected: "project/config.j2.yaml", expectedMarked: false},
+		{name: "project/.j2", expected: "project/.j2", expectedMarked: false},
+		{name: ".jinja", expected: ".jinja", expectedMarked: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, marked := core.TrimTemplateSuffix(tc.name)
+			if actual != tc.expected || marked != tc.expectedMarked {
+				t.Errorf("TrimTemplateSuffix(%q) = (%q, %t), expected (%q, %t)", tc.name, actual, marked, tc.expected, tc.expectedMarked)
+			}
+		})
+	}
+}
+
+func TestCheckRenderModes(t *testing.T) {
+	testCases := []struct {
+		name      string
+		modes     []string
+		expectErr bool
+	}{
+		{name: "default", modes: []string{""}, expectErr: false},
+		{name: "known modes", modes: []string{core.RenderModeAll, core.RenderModeSuffix}, expectErr: false},
+		{name: "unknown mode", modes: []string{"", "prefix"}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			layers := []entity.TemplateLayer{}
+			for _, mode := range tc.modes {
+				layers = append(layers, entity.TemplateLayer{RootPath: "template", Config: entity.TemplateConfig{RenderMode: mode}})
+			}
+
+			err := core.CheckRenderModes(layers)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("CheckRenderModes(%v) error = %v, expected error %t", tc.modes, err, tc.expectErr)
+			}
+		})
+	}
+}
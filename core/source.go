@@ -0,0 +1,81 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// BuiltinAbbreviations are the template source prefixes known out of the box
+var BuiltinAbbreviations = map[string]string{
+	"gh": "https://github.com/{0}.git",
+	"gl": "https://gitlab.com/{0}.git",
+	"bb": "https://bitbucket.org/{0}.git",
+}
+
+// SourceAbbreviations merges the user defined abbreviations on top of the builtin ones
+func SourceAbbreviations(userAbbreviations map[string]string) map[string]string {
+	abbreviations := make(map[string]string, len(BuiltinAbbreviations)+len(userAbbreviations))
+	for name, expansion := range BuiltinAbbreviations {
+		abbreviations[name] = expansion
+	}
+	for name, expansion := range userAbbreviations {
+		abbreviations[strings.ToLower(name)] = expansion
+	}
+	return abbreviations
+}
+
+// ResolveSource expands an abbreviated template source (e.g. gh:org/repo)
+// into a concrete one, leaving any other source untouched
+func ResolveSource(source string, abbreviations map[string]string) string {
+	prefix, rest, found := strings.Cut(source, ":")
+	if !found {
+		return source
+	}
+
+	expansion, ok := abbreviations[strings.ToLower(prefix)]
+	if !ok {
+		return source
+	}
+
+	return strings.ReplaceAll(expansion, "{0}", rest)
+}
+
+// SourceLocation resolves a template source into the location recorded by manifests: the URL of
+// remote repositories or the absolute path of local sources (local repositories included)
+func SourceLocation(source string, abbreviations map[string]string) (string, error) {
+	location := ResolveSource(source, abbreviations)
+	if strings.Contains(location, "://") || strings.HasPrefix(location, "git@") {
+		return location, nil
+	}
+	return filepath.Abs(location)
+}
+
+// IsRepositoryURL returns whether the source points to a git repository
+func IsRepositoryURL(source string) bool {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "git@", "file://"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(source, ".git")
+}
+
+// CloneRepository clones a git repository into dst and returns the commit checked out
+func CloneRepository(url string, dst string) (string, error) {
+	repository, err := git.PlainClone(dst, false, &git.CloneOptions{
+		URL:   url,
+		Depth: 1,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
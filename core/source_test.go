@@ -0,0 +1,93 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestResolveSource(t *testing.T) {
+	abbreviations := core.SourceAbbreviations(map[string]string{
+		"corp": "git@git.corp:templates/{0}.git",
+		"gh":   "https://github.example.com/{0}.git",
+	})
+
+	testCases := []struct {
+		source       string
+		expectedPath string
+	}{
+		{source: "corp:go-service", expectedPath: "git@git.corp:templates/go-service.git"},
+		{source: "CORP:go-service", expectedPath: "git@git.corp:templates/go-service.git"},
+		{source: "gh:org/repo", expectedPath: "https://github.example.com/org/repo.git"},
+		{source: "gl:group/repo", expectedPath: "https://gitlab.com/group/repo.git"},
+		{source: "git@github.com:org/repo.git", expectedPath: "git@github.com:org/repo.git"},
+		{source: "https://github.com/org/repo.git", expectedPath: "https://github.com/org/repo.git"},
+		{source: "./example", expectedPath: "./example"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			actual := core.ResolveSource(tc.source, abbreviations)
+			if actual != tc.expectedPath {
+				t.Errorf("ResolveSource(%q) = %q, expected %q", tc.source, actual, tc.expectedPath)
+			}
+		})
+	}
+}
+
+func TestIsRepositoryURL(t *testing.T) {
+	testCases := []struct {
+		source   string
+		expected bool
+	}{
+		{source: "https://github.com/org/repo.git", expected: true},
+		{source: "git@git.corp:templates/go-service.git", expected: true},
+		{source: "ssh://git@git.corp/templates/go-service", expected: true},
+		{source: "../templates/go-service.git", expected: true},
+		{source: "./example", expected: false},
+		{source: "/home/user/templates", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			actual := core.IsRepositoryURL(tc.source)
+			if actual != tc.expected {
+				t.Errorf("IsRepositoryURL(%q) = %t, expected %t", tc.source, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSourceLocation(t *testing.T) {
+	abbreviations := core.SourceAbbreviations(map[string]string{"corp": "git@git.corp:templates/{0}.git"})
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		source   string
+		expected string
+	}{
+		{source: "corp:go-service", expected: "git@git.corp:templates/go-service.git"},
+		{source: "gh:org/repo", expected: "https://github.com/org/repo.git"},
+		{source: "ssh://git@git.corp/templates/go-service", expected: "ssh://git@git.corp/templates/go-service"},
+		{source: "./example", expected: filepath.Join(workingDir, "example")},
+		{source: "../templates/go-service.git", expected: filepath.Join(filepath.Dir(workingDir), "templates", "go-service.git")},
+		{source: "/home/user/templates", expected: "/home/user/templates"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			actual, err := core.SourceLocation(tc.source, abbreviations)
+			if err != nil {
+				t.Fatalf("SourceLocation(%q) failed: %v", tc.source, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("SourceLocation(%q) = %q, expected %q", tc.source, actual, tc.expected)
+			}
+		})
+	}
+}
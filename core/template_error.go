@@ -0,0 +1,212 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// Jinja reports the failing template (<template> being the rendered one) and line
+	rgxJinjaLocation = regexp.MustCompile(`File "([^"]+)", line (\d+)`)
+	// text/template reports template:line[:column]
+	rgxGoTemplateLocation = regexp.MustCompile(`^template: [^:]+:(\d+)(?::(\d+))?: `)
+
+	rgxJinjaUndefinedAttribute = regexp.MustCompile(`has no attribute '([^']+)'`)
+	rgxJinjaUndefinedName      = regexp.MustCompile(`'([^']+)' is undefined`)
+	rgxGoTemplateMissingKey    = regexp.MustCompile(`at <([^>]+)>: map has no entry for key "([^"]+)"`)
+)
+
+// TemplateError is a failed render located in the template file
+type TemplateError struct {
+	// File is the template relative path of the rendered file (or path name)
+	File string
+	// Include is the included template the error happened in (if not the file itself)
+	Include string
+	Line    int
+	Column  int
+	Snippet string
+	Message string
+
+	// Variable is the undefined variable (if any) along with the variables
+	// available to templates and the closest ones to it
+	Variable    string
+	Available   []string
+	Suggestions []string
+}
+
+func (e *TemplateError) Error() string {
+	location := e.File
+	if e.Include != "" {
+		location += " (included " + e.Include + ")"
+	}
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			location += ":" + strconv.Itoa(e.Column)
+		}
+	}
+
+	var builder strings.Builder
+	if location != "" {
+		builder.WriteString(location + ": ")
+	}
+	builder.WriteString(e.Message)
+
+	if e.Snippet != "" {
+		prefix := fmt.Sprintf("    %d | ", e.Line)
+		builder.WriteString("\n" + prefix + e.Snippet)
+		if e.Column > 0 {
+			builder.WriteString("\n" + strings.Repeat(" ", len(prefix)-2) + "| " + strings.Repeat(" ", e.Column-1) + "^")
+		}
+	}
+
+	if e.Variable != "" {
+		if len(e.Suggestions) > 0 {
+			builder.WriteString("\n    did you mean " + strings.Join(e.Suggestions, " or ") + "?")
+		}
+		builder.WriteString("\n    available variables: " + strings.Join(e.Available, ", "))
+	}
+	return builder.String()
+}
+
+// WithTemplateFile sets the template relative path of the file a render error comes from
+func WithTemplateFile(err error, file string) error {
+	var templateErr *TemplateError
+	if errors.As(err, &templateErr) {
+		located := *templateErr
+		located.File = file
+		return &located
+	}
+	return fmt.Errorf("%s: %w", file, err)
+}
+
+// newTemplateError details an error of the template engine rendering source
+// (params are the variables available to the template as scaffold.<name>)
+func newTemplateError(source string, err error, params map[string]any) *TemplateError {
+	message := err.Error()
+	templateErr := &TemplateError{Message: strings.TrimSpace(message)}
+
+	if locations := rgxJinjaLocation.FindAllStringSubmatch(message, -1); len(locations) > 0 {
+		// Jinja traceback: the last location is the innermost one
+		location := locations[len(locations)-1]
+		if location[1] != "<template>" && location[1] != "<unknown>" {
+			templateErr.Include = location[1]
+		}
+		templateErr.Line, _ = strconv.Atoi(location[2])
+
+		lines := strings.Split(strings.TrimSpace(message), "\n")
+		templateErr.Message = lines[len(lines)-1]
+	} else if location := rgxGoTemplateLocation.FindStringSubmatch(message); location != nil {
+		templateErr.Line, _ = strconv.Atoi(location[1])
+		templateErr.Column, _ = strconv.Atoi(location[2])
+		templateErr.Message = strings.TrimPrefix(message, location[0])
+	}
+
+	// Undefined variables (attribute means scaffold.<name>)
+	name, isAttribute := "", false
+	if match := rgxJinjaUndefinedAttribute.FindStringSubmatch(message); match != nil {
+		name, isAttribute = match[1], true
+		templateErr.Variable = "scaffold." + name
+	} else if match := rgxJinjaUndefinedName.FindStringSubmatch(message); match != nil {
+		name = match[1]
+		templateErr.Variable = name
+	} else if match := rgxGoTemplateMissingKey.FindStringSubmatch(message); match != nil {
+		name, isAttribute = match[2], true
+		templateErr.Variable = strings.TrimPrefix(match[1], ".")
+	}
+
+	if templateErr.Variable != "" {
+		templateErr.Message = "undefined variable " + templateErr.Variable
+		templateErr.Available, templateErr.Suggestions = variableSuggestions(name, isAttribute, params)
+	}
+
+	// Snippet of the failing line (unknown for errors inside included templates)
+	sourceLines := strings.Split(source, "\n")
+	if templateErr.Include == "" && templateErr.Line > 0 && templateErr.Line <= len(sourceLines) {
+		templateErr.Snippet = strings.TrimRight(sourceLines[templateErr.Line-1], "\r")
+		if name != "" {
+			if index := undefinedNameIndex(templateErr.Snippet, name); index >= 0 {
+				templateErr.Column = utf8.RuneCountInString(templateErr.Snippet[:index]) + 1
+			}
+		}
+	}
+
+	return templateErr
+}
+
+// undefinedNameIndex returns the byte index of name used as an identifier in line (or -1)
+func undefinedNameIndex(line string, name string) int {
+	rgxName := regexp.MustCompile(`(^|[^\w])(` + regexp.QuoteMeta(name) + `)([^\w]|$)`)
+	location := rgxName.FindStringSubmatchIndex(line)
+	if location == nil {
+		return -1
+	}
+	return location[4]
+}
+
+// variableSuggestions lists the variables available to templates and the closest ones to
+// an undefined name (an attribute of scaffold, or a top-level name)
+func variableSuggestions(name string, isAttribute bool, params map[string]any) ([]string, []string) {
+	available := make([]string, 0, len(params))
+	for key := range params {
+		available = append(available, "scaffold."+key)
+	}
+	sort.Strings(available)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	candidates := []candidate{}
+	maxDistance := max(2, utf8.RuneCountInString(name)/3)
+	for key := range params {
+		distance := levenshtein(strings.ToLower(name), strings.ToLower(key))
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{name: "scaffold." + key, distance: distance})
+		}
+	}
+	if !isAttribute && levenshtein(name, "scaffold") <= maxDistance {
+		candidates = append(candidates, candidate{name: "scaffold", distance: levenshtein(name, "scaffold")})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	suggestions := []string{}
+	for _, c := range candidates[:min(len(candidates), 3)] {
+		suggestions = append(suggestions, c.name)
+	}
+	return available, suggestions
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a string, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		current := make([]int, len(runesB)+1)
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(runesB)]
+}
@@ -0,0 +1,185 @@
+package core_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestTemplateError(t *testing.T) {
+	params := map[string]any{"project_name": "My Service", "license": "MIT"}
+	available := []string{"scaffold.license", "scaffold.project_name"}
+
+	testCases := []struct {
+		engine              string
+		name                string
+		template            string
+		expectedLine        int
+		expectedColumn      int
+		expectedVariable    string
+		expectedSuggestions []string
+	}{
+		{
+			engine:              core.JinjaEngineName,
+			name:                "undefined attribute",
+			template:            "package main\n\n// {{ scaffold.projct_name }}\n",
+			expectedLine:        3,
+			expectedColumn:      16,
+			expectedVariable:    "scaffold.projct_name",
+			expectedSuggestions: []string{"scaffold.project_name"},
+		},
+		{
+			engine:              core.JinjaEngineName,
+			name:                "undefined top-level name",
+			template:            "name: {{ project_name }}",
+			expectedLine:        1,
+			expectedColumn:      10,
+			expectedVariable:    "project_name",
+			expectedSuggestions: []string{"scaffold.project_name"},
+		},
+		{
+			engine:              core.JinjaEngineName,
+			name:                "no close variable",
+			template:            "{{ scaffold.database }}",
+			expectedLine:        1,
+			expectedColumn:      13,
+			expectedVariable:    "scaffold.database",
+			expectedSuggestions: []string{},
+		},
+		{
+			engine:       core.JinjaEngineName,
+			name:         "syntax error",
+			template:     "a\n\n{% if %}",
+			expectedLine: 3,
+		},
+		{
+			engine:              core.GoTemplateEngineName,
+			name:                "missing key",
+			template:            "package main\n\n// {{ .scaffold.licnse }}\n",
+			expectedLine:        3,
+			expectedColumn:      17,
+			expectedVariable:    "scaffold.licnse",
+			expectedSuggestions: []string{"scaffold.license"},
+		},
+		{
+			engine:       core.GoTemplateEngineName,
+			name:         "syntax error",
+			template:     "a\n{{ if }}",
+			expectedLine: 2,
+		},
+	}
+
+	engines := map[string]core.Engine{}
+	for _, name := range []string{core.JinjaEngineName, core.GoTemplateEngineName} {
+		engine, err := core.NewEngine(name, params, core.EngineOptions{Workers: 1})
+		if err != nil {
+			t.Fatalf("NewEngine(%q) failed: %v", name, err)
+		}
+		defer engine.Close()
+		engines[name] = engine
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.engine+"/"+tc.name, func(t *testing.T) {
+			_, err := engines[tc.engine].RenderString(tc.template, entity.Delimiters{})
+			if err == nil {
+				t.Fatalf("RenderString(%q) succeeded, expected an error", tc.template)
+			}
+
+			err = core.WithTemplateFile(err, "project/main.go")
+			var templateErr *core.TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("RenderString(%q) error %T is not a *core.TemplateError", tc.template, err)
+			}
+
+			if templateErr.File != "project/main.go" {
+				t.Errorf("File = %q, expected %q", templateErr.File, "project/main.go")
+			}
+			if templateErr.Line != tc.expectedLine {
+				t.Errorf("Line = %d, expected %d (%v)", templateErr.Line, tc.expectedLine, err)
+			}
+			expectedSnippet := strings.Split(tc.template, "\n")[tc.expectedLine-1]
+			if templateErr.Snippet != expectedSnippet {
+				t.Errorf("Snippet = %q, expected %q", templateErr.Snippet, expectedSnippet)
+			}
+			if templateErr.Variable != tc.expectedVariable {
+				t.Errorf("Variable = %q, expected %q", templateErr.Variable, tc.expectedVariable)
+			}
+			if tc.expectedVariable == "" {
+				return
+			}
+
+			if templateErr.Column != tc.expectedColumn {
+				t.Errorf("Column = %d, expected %d", templateErr.Column, tc.expectedColumn)
+			}
+			if !reflect.DeepEqual(templateErr.Suggestions, tc.expectedSuggestions) {
+				t.Errorf("Suggestions = %v, expected %v", templateErr.Suggestions, tc.expectedSuggestions)
+			}
+			if !reflect.DeepEqual(templateErr.Available, available) {
+				t.Errorf("Available = %v, expected %v", templateErr.Available, available)
+			}
+			if !strings.HasPrefix(err.Error(), "project/main.go:") {
+				t.Errorf("Error() = %q, expected it to start with the file location", err.Error())
+			}
+		})
+	}
+}
+
+func TestLenientUndefined(t *testing.T) {
+	testCases := []struct {
+		name     string
+		engine   string
+		template string
+		expected string
+	}{
+		{name: "jinja", engine: core.JinjaEngineName, template: "[{{ scaffold.missing }}]", expected: "[]"},
+		{name: "gotemplate", engine: core.GoTemplateEngineName, template: "[{{ .scaffold.missing }}]", expected: "[]"},
+		{
+			name:     "gotemplate literal no value",
+			engine:   core.GoTemplateEngineName,
+			template: "<no value> {{ .scaffold.name }}{{ .scaffold.missing }}",
+			expected: "<no value> svc",
+		},
+		{
+			name:     "gotemplate nested actions",
+			engine:   core.GoTemplateEngineName,
+			template: "{{ if .scaffold.name }}[{{ .scaffold.missing }}]{{ end }}{{ range .scaffold.tags }}[{{ . }}{{ $.scaffold.missing }}]{{ end }}",
+			expected: "[][go][cli]",
+		},
+		{
+			name:     "gotemplate variables and defined templates",
+			engine:   core.GoTemplateEngineName,
+			template: `{{ define "value" }}[{{ . }}]{{ end }}{{ $value := .scaffold.missing }}{{ $value }}{{ template "value" .scaffold.missing }}`,
+			expected: "[]",
+		},
+		{
+			name:     "gotemplate pipelines",
+			engine:   core.GoTemplateEngineName,
+			template: `{{ .scaffold.name | upper }} {{ printf "%d" 3 }}`,
+			expected: "SVC 3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := map[string]any{"name": "svc", "tags": []any{"go", "cli"}}
+			engine, err := core.NewEngine(tc.engine, params, core.EngineOptions{Workers: 1, LenientUndefined: true})
+			if err != nil {
+				t.Fatalf("NewEngine(%q) failed: %v", tc.engine, err)
+			}
+			defer engine.Close()
+
+			actual, err := engine.RenderString(tc.template, entity.Delimiters{})
+			if err != nil {
+				t.Fatalf("RenderString(%q) failed: %v", tc.template, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("RenderString(%q) = %q, expected %q", tc.template, actual, tc.expected)
+			}
+		})
+	}
+}
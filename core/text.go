@@ -0,0 +1,161 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/copito/goscaffold/entity"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+const (
+	NewlineLF       = "lf"
+	NewlineCRLF     = "crlf"
+	NewlinePreserve = "preserve"
+
+	BOMKeep  = "keep"
+	BOMStrip = "strip"
+	BOMAdd   = "add"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// TextSource describes the text read from a template file (needed to generate it back)
+type TextSource struct {
+	HasBOM bool
+}
+
+// MergeTextOptions overrides the text options of base with the ones set in override
+func MergeTextOptions(base entity.TextOptions, override entity.TextOptions) entity.TextOptions {
+	merged := base
+	if override.Newline != "" {
+		merged.Newline = override.Newline
+	}
+	if override.KeepTrailingNewline != nil {
+		merged.KeepTrailingNewline = override.KeepTrailingNewline
+	}
+	if override.BOM != "" {
+		merged.BOM = override.BOM
+	}
+	if override.Encoding != "" {
+		merged.Encoding = override.Encoding
+	}
+	return merged
+}
+
+// FileTextOptions returns the text options used for a template path of a layer
+// (template options overridden by every matching text override)
+func FileTextOptions(config entity.TemplateConfig, templatePath string) (entity.TextOptions, error) {
+	options := config.Text
+	for _, override := range config.TextOverrides {
+		matched, err := MatchAnyGlobOrParent([]string{override.Path}, templatePath)
+		if err != nil {
+			return options, err
+		}
+		if matched {
+			options = MergeTextOptions(options, override.Text)
+		}
+	}
+	return options, CheckTextOptions(options)
+}
+
+// CheckTextOptions verifies the text options hold known values
+func CheckTextOptions(options entity.TextOptions) error {
+	switch options.Newline {
+	case "", NewlineLF, NewlineCRLF, NewlinePreserve:
+	default:
+		return fmt.Errorf("unknown newline %s (expected %s, %s or %s)", options.Newline, NewlineLF, NewlineCRLF, NewlinePreserve)
+	}
+
+	switch options.BOM {
+	case "", BOMKeep, BOMStrip, BOMAdd:
+	default:
+		return fmt.Errorf("unknown bom %s (expected %s, %s or %s)", options.BOM, BOMKeep, BOMStrip, BOMAdd)
+	}
+
+	_, err := TextEncoding(options.Encoding)
+	return err
+}
+
+// TextEncoding returns the encoding with the given name (nil for UTF-8)
+func TextEncoding(name string) (encoding.Encoding, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	textEncoding, err := ianaindex.IANA.Encoding(name)
+	if err != nil || textEncoding == nil {
+		// latin-1 is known as latin1
+		textEncoding, err = ianaindex.IANA.Encoding(strings.ReplaceAll(name, "-", ""))
+	}
+	if err != nil || textEncoding == nil {
+		return nil, fmt.Errorf("unsupported encoding %s", name)
+	}
+
+	if utf8Name, _ := ianaindex.IANA.Name(textEncoding); utf8Name == "UTF-8" {
+		return nil, nil
+	}
+	return textEncoding, nil
+}
+
+// DecodeText converts the content of a template file to UTF-8 (without BOM)
+func DecodeText(data []byte, options entity.TextOptions) (string, TextSource, error) {
+	textEncoding, err := TextEncoding(options.Encoding)
+	if err != nil {
+		return "", TextSource{}, err
+	}
+
+	source := TextSource{}
+	data, source.HasBOM = bytes.CutPrefix(data, utf8BOM)
+	if textEncoding != nil {
+		data, err = textEncoding.NewDecoder().Bytes(data)
+		if err != nil {
+			return "", source, fmt.Errorf("decoding %s content: %w", options.Encoding, err)
+		}
+	}
+
+	return string(data), source, nil
+}
+
+// EncodeText generates the content of a file from its (rendered) text applying the text options
+func EncodeText(text string, source TextSource, options entity.TextOptions) ([]byte, error) {
+	// Both engines keep the trailing newline when rendering, only one newline is dropped (like Jinja)
+	if options.KeepTrailingNewline != nil && !*options.KeepTrailingNewline {
+		if trimmed, found := strings.CutSuffix(text, "\n"); found {
+			text = strings.TrimSuffix(trimmed, "\r")
+		}
+	}
+
+	switch options.Newline {
+	case NewlineLF:
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+	case NewlineCRLF:
+		text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	}
+
+	data := []byte(text)
+	textEncoding, err := TextEncoding(options.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	if textEncoding != nil {
+		data, err = textEncoding.NewEncoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("encoding content as %s: %w", options.Encoding, err)
+		}
+	}
+
+	switch options.BOM {
+	case BOMAdd:
+		return append(append([]byte{}, utf8BOM...), data...), nil
+	case BOMStrip:
+		return data, nil
+	default:
+		if source.HasBOM {
+			return append(append([]byte{}, utf8BOM...), data...), nil
+		}
+		return data, nil
+	}
+}
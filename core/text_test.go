@@ -0,0 +1,189 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/copito/goscaffold/entity"
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	keep := true
+	drop := false
+
+	testCases := []struct {
+		name     string
+		options  entity.TextOptions
+		content  string
+		rendered func(text string) string
+		expected string
+	}{
+		{
+			name:     "trailing newline kept",
+			content:  "a\nb\n",
+			expected: "a\nb\n",
+		},
+		{
+			name:     "trailing crlf kept",
+			options:  entity.TextOptions{KeepTrailingNewline: &keep},
+			content:  "a\r\nb\r\n",
+			expected: "a\r\nb\r\n",
+		},
+		{
+			name:     "trailing newlines kept",
+			content:  "a\n\n",
+			expected: "a\n\n",
+		},
+		{
+			name:     "trailing newline removed",
+			options:  entity.TextOptions{KeepTrailingNewline: &drop},
+			content:  "a\nb\n",
+			expected: "a\nb",
+		},
+		{
+			name:     "only the last trailing newline removed",
+			options:  entity.TextOptions{KeepTrailingNewline: &drop},
+			content:  "a\n\n",
+			expected: "a\n",
+		},
+		{
+			name:     "trailing crlf removed",
+			options:  entity.TextOptions{KeepTrailingNewline: &drop},
+			content:  "a\r\n",
+			expected: "a",
+		},
+		{
+			name:     "no trailing newline added",
+			content:  "a\nb",
+			expected: "a\nb",
+		},
+		{
+			name:     "mixed newlines to lf",
+			options:  entity.TextOptions{Newline: core.NewlineLF},
+			content:  "a\r\nb\nc\r\n",
+			expected: "a\nb\nc\n",
+		},
+		{
+			name:     "mixed newlines to crlf",
+			options:  entity.TextOptions{Newline: core.NewlineCRLF},
+			content:  "a\r\nb\nc\n",
+			expected: "a\r\nb\r\nc\r\n",
+		},
+		{
+			name:     "mixed newlines preserved",
+			options:  entity.TextOptions{Newline: core.NewlinePreserve},
+			content:  "a\r\nb\n",
+			expected: "a\r\nb\n",
+		},
+		{
+			name:     "bom kept",
+			content:  "\xEF\xBB\xBFa\n",
+			rendered: func(text string) string { return text },
+			expected: "\xEF\xBB\xBFa\n",
+		},
+		{
+			name:     "bom stripped",
+			options:  entity.TextOptions{BOM: core.BOMStrip},
+			content:  "\xEF\xBB\xBFa\n",
+			expected: "a\n",
+		},
+		{
+			name:     "bom added",
+			options:  entity.TextOptions{BOM: core.BOMAdd},
+			content:  "a\n",
+			expected: "\xEF\xBB\xBFa\n",
+		},
+		{
+			name:    "latin-1 content",
+			options: entity.TextOptions{Encoding: "latin-1"},
+			content: "caf\xE9 {{ name }}\n",
+			rendered: func(text string) string {
+				if text != "café {{ name }}\n" {
+					return "unexpected decoded text " + text
+				}
+				return "café José\n"
+			},
+			expected: "caf\xE9 Jos\xE9\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := core.CheckTextOptions(tc.options); err != nil {
+				t.Fatalf("CheckTextOptions failed: %v", err)
+			}
+
+			text, source, err := core.DecodeText([]byte(tc.content), tc.options)
+			if err != nil {
+				t.Fatalf("DecodeText(%q) failed: %v", tc.content, err)
+			}
+			if tc.rendered != nil {
+				text = tc.rendered(text)
+			}
+
+			actual, err := core.EncodeText(text, source, tc.options)
+			if err != nil {
+				t.Fatalf("EncodeText(%q) failed: %v", text, err)
+			}
+			if string(actual) != tc.expected {
+				t.Errorf("EncodeText(%q) = %q, expected %q", text, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFileTextOptions(t *testing.T) {
+	config := entity.TemplateConfig{
+		Text: entity.TextOptions{Newline: core.NewlineLF},
+		TextOverrides: []entity.TextOverride{
+			{Path: "**/*.bat", Text: entity.TextOptions{Newline: core.NewlineCRLF}},
+			{Path: "*/legacy", Text: entity.TextOptions{Encoding: "latin1"}},
+		},
+	}
+
+	testCases := []struct {
+		templatePath string
+		expected     entity.TextOptions
+	}{
+		{templatePath: "project/main.go", expected: entity.TextOptions{Newline: core.NewlineLF}},
+		{templatePath: "project/scripts/build.bat", expected: entity.TextOptions{Newline: core.NewlineCRLF}},
+		{templatePath: "project/legacy/readme.txt", expected: entity.TextOptions{Newline: core.NewlineLF, Encoding: "latin1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.templatePath, func(t *testing.T) {
+			actual, err := core.FileTextOptions(config, tc.templatePath)
+			if err != nil {
+				t.Fatalf("FileTextOptions(%q) failed: %v", tc.templatePath, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("FileTextOptions(%q) = %+v, expected %+v", tc.templatePath, actual, tc.expected)
+			}
+		})
+	}
+
+	for _, options := range []entity.TextOptions{{Newline: "cr"}, {BOM: "maybe"}, {Encoding: "klingon"}} {
+		if err := core.CheckTextOptions(options); err == nil {
+			t.Errorf("CheckTextOptions(%+v) accepted invalid options", options)
+		}
+	}
+}
+
+func TestDetectBinaryFileEncoding(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "latin1.txt")
+	if err := os.WriteFile(src, []byte("caf\xE9\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	isBinary, _, err := core.DetectBinaryFile(src, "")
+	if err != nil || !isBinary {
+		t.Errorf("DetectBinaryFile(utf-8) = %t, %v, expected binary content", isBinary, err)
+	}
+
+	isBinary, reason, err := core.DetectBinaryFile(src, "latin-1")
+	if err != nil || isBinary {
+		t.Errorf("DetectBinaryFile(latin-1) = %t (%s), %v, expected text content", isBinary, reason, err)
+	}
+}
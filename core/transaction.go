@@ -0,0 +1,136 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// OutputTransaction replaces output paths only once all of their new content is ready: contents are
+// staged next to their destination then renamed over it, the replaced paths being kept aside until
+// Commit so that Rollback restores the output folder as it was
+type OutputTransaction struct {
+	fs      afero.Fs
+	suffix  string
+	staged  []outputReplacement
+	applied []outputReplacement
+}
+
+// outputReplacement is an output path along with its staged content and the backup of its
+// previous content ("" when it did not exist)
+type outputReplacement struct {
+	outputPath string
+	stagedPath string
+	backupPath string
+}
+
+func NewOutputTransaction(fs afero.Fs) *OutputTransaction {
+	return &OutputTransaction{fs: fs, suffix: strconv.FormatInt(time.Now().UnixNano(), 36)}
+}
+
+// Stage returns the temporary path (in the same folder) the new content of an output path
+// must be written to
+func (t *OutputTransaction) Stage(outputPath string) string {
+	stagedPath := t.siblingPath(outputPath, "new")
+	t.staged = append(t.staged, outputReplacement{outputPath: outputPath, stagedPath: stagedPath})
+	return stagedPath
+}
+
+// Apply renames the staged contents over their output paths (existing paths are backed up first)
+func (t *OutputTransaction) Apply() error {
+	for len(t.staged) > 0 {
+		replacement := t.staged[0]
+		_, err := lstat(t.fs, replacement.outputPath)
+		switch {
+		case err == nil:
+			replacement.backupPath, err = t.backup(replacement.outputPath)
+			if err != nil {
+				return fmt.Errorf("backing up %s: %w", replacement.outputPath, err)
+			}
+		case !os.IsNotExist(err):
+			return err
+		}
+
+		err = t.fs.Rename(replacement.stagedPath, replacement.outputPath)
+		if err != nil {
+			return err
+		}
+		t.staged = t.staged[1:]
+		t.applied = append(t.applied, replacement)
+	}
+	return nil
+}
+
+// Rollback removes the staged contents and restores the replaced paths
+func (t *OutputTransaction) Rollback() error {
+	errs := []error{}
+	for _, replacement := range t.staged {
+		err := t.fs.RemoveAll(replacement.stagedPath)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.staged = nil
+
+	for i := len(t.applied) - 1; i >= 0; i-- {
+		replacement := t.applied[i]
+		var err error
+		if replacement.backupPath == "" {
+			err = t.fs.RemoveAll(replacement.outputPath)
+		} else {
+			err = t.fs.Rename(replacement.backupPath, replacement.outputPath)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.applied = nil
+	return errors.Join(errs...)
+}
+
+// Commit removes the backups of the replaced paths
+func (t *OutputTransaction) Commit() error {
+	errs := []error{}
+	for _, replacement := range t.applied {
+		if replacement.backupPath == "" {
+			continue
+		}
+		err := t.fs.Remove(replacement.backupPath)
+		if err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	t.applied = nil
+	return errors.Join(errs...)
+}
+
+// backup keeps the current content of an output path aside: hard linked so the path itself is
+// replaced atomically, or moved when hard links are not available
+func (t *OutputTransaction) backup(outputPath string) (string, error) {
+	backupPath := t.siblingPath(outputPath, "old")
+	if _, isOsFs := t.fs.(*afero.OsFs); isOsFs {
+		if os.Link(outputPath, backupPath) == nil {
+			return backupPath, nil
+		}
+	}
+	return backupPath, t.fs.Rename(outputPath, backupPath)
+}
+
+// siblingPath returns a hidden temporary path next to an output path
+func (t *OutputTransaction) siblingPath(outputPath string, kind string) string {
+	return path.Join(path.Dir(outputPath), fmt.Sprintf(".%s.scaffold-%s-%s", path.Base(outputPath), kind, t.suffix))
+}
+
+// lstat describes a path of fs without following symlinks (when fs supports it)
+func lstat(fs afero.Fs, name string) (os.FileInfo, error) {
+	if lstater, ok := fs.(afero.Lstater); ok {
+		info, _, err := lstater.LstatIfPossible(name)
+		return info, err
+	}
+	return fs.Stat(name)
+}
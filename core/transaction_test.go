@@ -0,0 +1,142 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+	"github.com/spf13/afero"
+)
+
+// outputFiles returns the content of every file of a folder (by slash separated relative path)
+func outputFiles(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.Walk(root, func(pathValue string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(pathValue)
+		if err != nil {
+			return err
+		}
+		relativePath, _ := filepath.Rel(root, pathValue)
+		files[filepath.ToSlash(relativePath)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// stageFiles stages and writes new contents of output files
+func stageFiles(t *testing.T, transaction *core.OutputTransaction, root string, files map[string]string) {
+	t.Helper()
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		stagedPath := transaction.Stage(filepath.Join(root, name))
+		if err := os.WriteFile(stagedPath, []byte(files[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOutputTransaction(t *testing.T) {
+	existing := map[string]string{"svc/a.txt": "old a\n", "svc/keep.txt": "keep\n"}
+	generated := map[string]string{"svc/a.txt": "new a\n", "svc/b.txt": "new b\n"}
+
+	testCases := []struct {
+		name     string
+		apply    bool
+		commit   bool
+		expected map[string]string
+	}{
+		{
+			name:     "rollback before apply",
+			expected: existing,
+		},
+		{
+			name:     "rollback after apply",
+			apply:    true,
+			expected: existing,
+		},
+		{
+			name:     "commit",
+			apply:    true,
+			commit:   true,
+			expected: map[string]string{"svc/a.txt": "new a\n", "svc/b.txt": "new b\n", "svc/keep.txt": "keep\n"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFiles(t, root, existing)
+
+			transaction := core.NewOutputTransaction(afero.NewOsFs())
+			stageFiles(t, transaction, root, generated)
+
+			// Nothing is replaced until applied
+			if actual := outputFiles(t, root); actual["svc/a.txt"] != "old a\n" || actual["svc/b.txt"] != "" {
+				t.Fatalf("staging replaced output files: %v", actual)
+			}
+
+			if tc.apply {
+				if err := transaction.Apply(); err != nil {
+					t.Fatalf("Apply failed: %v", err)
+				}
+			}
+			if tc.commit {
+				if err := transaction.Commit(); err != nil {
+					t.Fatalf("Commit failed: %v", err)
+				}
+			} else if err := transaction.Rollback(); err != nil {
+				t.Fatalf("Rollback failed: %v", err)
+			}
+
+			actual := outputFiles(t, root)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("output files = %v, expected %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOutputTransactionSymlink(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"a.txt": "a\n", "b.txt": "b\n"})
+	linkPath := filepath.Join(root, "link")
+	if err := os.Symlink("a.txt", linkPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replaced symlinks are replaced themselves (not written through)
+	transaction := core.NewOutputTransaction(afero.NewOsFs())
+	if err := os.Symlink("b.txt", transaction.Stage(linkPath)); err != nil {
+		t.Fatal(err)
+	}
+	if err := transaction.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if target, _ := os.Readlink(linkPath); target != "b.txt" {
+		t.Errorf("link target = %q, expected %q", target, "b.txt")
+	}
+
+	if err := transaction.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if target, _ := os.Readlink(linkPath); target != "a.txt" {
+		t.Errorf("restored link target = %q, expected %q", target, "a.txt")
+	}
+	if actual := outputFiles(t, root); !reflect.DeepEqual(actual, map[string]string{"a.txt": "a\n", "b.txt": "b\n", "link": "a\n"}) {
+		t.Errorf("output files = %v", actual)
+	}
+}
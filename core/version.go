@@ -0,0 +1,98 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseVersion parses a semantic version (e.g. v1.2.3) into its numerical parts
+// (pre-release and build suffixes are ignored)
+func ParseVersion(version string) ([3]int, error) {
+	parts := [3]int{}
+
+	cleanVersion := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if pos := strings.IndexAny(cleanVersion, "-+"); pos != -1 {
+		cleanVersion = cleanVersion[:pos]
+	}
+
+	numbers := strings.Split(cleanVersion, ".")
+	if cleanVersion == "" || len(numbers) > 3 {
+		return parts, fmt.Errorf("invalid version %q", version)
+	}
+
+	for i, number := range numbers {
+		value, err := strconv.Atoi(number)
+		if err != nil || value < 0 {
+			return parts, fmt.Errorf("invalid version %q", version)
+		}
+		parts[i] = value
+	}
+	return parts, nil
+}
+
+// CompareVersions returns -1, 0 or 1 whether version a is lower, equal or greater than b
+func CompareVersions(a string, b string) (int, error) {
+	partsA, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+
+	partsB, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range partsA {
+		if partsA[i] < partsB[i] {
+			return -1, nil
+		}
+		if partsA[i] > partsB[i] {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// CheckVersionConstraint returns whether the version satisfies every comma separated
+// constraint (e.g. ">=0.1.0, <1.0.0")
+func CheckVersionConstraint(version string, constraint string) (bool, error) {
+	for _, rule := range strings.Split(constraint, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		expected := strings.TrimLeft(rule, "<>=!")
+		operator := rule[:len(rule)-len(expected)]
+		expected = strings.TrimSpace(expected)
+
+		comparison, err := CompareVersions(version, expected)
+		if err != nil {
+			return false, err
+		}
+
+		var satisfied bool
+		switch operator {
+		case ">=":
+			satisfied = comparison >= 0
+		case ">":
+			satisfied = comparison > 0
+		case "<=":
+			satisfied = comparison <= 0
+		case "<":
+			satisfied = comparison < 0
+		case "=", "==", "":
+			satisfied = comparison == 0
+		case "!=":
+			satisfied = comparison != 0
+		default:
+			return false, fmt.Errorf("invalid version constraint %q", rule)
+		}
+
+		if !satisfied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
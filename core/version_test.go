@@ -0,0 +1,44 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/copito/goscaffold/core"
+)
+
+func TestCheckVersionConstraint(t *testing.T) {
+	testCases := []struct {
+		version     string
+		constraint  string
+		expected    bool
+		expectError bool
+	}{
+		{version: "0.0.3", constraint: ">=0.1.0", expected: false},
+		{version: "0.1.0", constraint: ">=0.1.0", expected: true},
+		{version: "v1.2.3", constraint: ">= 0.1.0, < 2.0", expected: true},
+		{version: "2.0.0", constraint: ">=0.1.0, <2.0", expected: false},
+		{version: "1.0.0-rc1", constraint: "1.0.0", expected: true},
+		{version: "1.0.0", constraint: "!=1.0.0", expected: false},
+		{version: "1.0.0", constraint: "", expected: true},
+		{version: "1.0.0", constraint: "~>1.0", expectError: true},
+		{version: "latest", constraint: ">=0.1.0", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version+" "+tc.constraint, func(t *testing.T) {
+			actual, err := core.CheckVersionConstraint(tc.version, tc.constraint)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("CheckVersionConstraint(%q, %q) expected an error", tc.version, tc.constraint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckVersionConstraint(%q, %q) failed: %v", tc.version, tc.constraint, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("CheckVersionConstraint(%q, %q) = %t, expected %t", tc.version, tc.constraint, actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package entity
+
+type Catalog struct {
+	Templates []CatalogTemplate `mapstructure:"templates" json:"templates"`
+}
+
+type CatalogTemplate struct {
+	Name        string   `mapstructure:"name" json:"name"`
+	Description string   `mapstructure:"description" json:"description"`
+	Path        string   `mapstructure:"path" json:"path"`
+	Tags        []string `mapstructure:"tags" json:"tags"`
+
+	// Metadata declared by the template configuration itself (if any)
+	Template *TemplateMetadata `mapstructure:"-" json:"template,omitempty"`
+}
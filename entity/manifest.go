@@ -0,0 +1,32 @@
+package entity
+
+// Manifest records how a project was generated (written into the generated project)
+type Manifest struct {
+	Template        ManifestTemplate `yaml:"template"`
+	ScaffoldVersion string           `yaml:"scaffold_version"`
+	GeneratedAt     string           `yaml:"generated_at"`
+	Answers         map[string]any   `yaml:"answers"`
+	Files           []ManifestFile   `yaml:"files"`
+}
+
+// ManifestTemplate identifies the template (and revision) a project was generated from
+type ManifestTemplate struct {
+	Source    string `yaml:"source"`
+	Directory string `yaml:"directory,omitempty"`
+	Commit    string `yaml:"commit,omitempty"`
+	Name      string `yaml:"name,omitempty"`
+	Version   string `yaml:"version,omitempty"`
+}
+
+// ManifestFile is a generated file (slash separated path relative to the project) along with its hash
+type ManifestFile struct {
+	Path   string `yaml:"path"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// ManifestConfig configures the manifest written into generated projects
+type ManifestConfig struct {
+	// Path of the manifest inside the generated project (default .scaffold/answers.yaml)
+	Path     string `mapstructure:"path"`
+	Disabled bool   `mapstructure:"disabled"`
+}
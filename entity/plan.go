@@ -0,0 +1,45 @@
+package entity
+
+import "os"
+
+// PlanEntry is a file/folder of the template along with where it is generated
+type PlanEntry struct {
+	SourcePath   string
+	TemplatePath string
+	RenderedPath string
+	Info         os.FileInfo
+
+	// Mode is the permissions of the generated file/folder and LinkTarget
+	// the (rendered) target of symlinks
+	Mode       os.FileMode
+	LinkTarget string
+
+	CopyWithoutRender bool
+	// Binary files are always copied byte-for-byte (text options do not apply)
+	Binary     bool
+	Delimiters Delimiters
+	Text       TextOptions
+	// Formatter reformats the rendered content (empty when not formatted)
+	Formatter string
+	// Vars are the loop variables bound when rendering the path and content
+	// (paths declaring a loop generate one entry per element)
+	Vars map[string]any
+	// Kept entries already exist in the output folder and are left as they are
+	Kept bool
+}
+
+// DryRunEntry is a path a dry-run would generate (Status is create, change, unchanged or keep
+// compared to the output folder on disk)
+type DryRunEntry struct {
+	Path   string `json:"path"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Size   int64  `json:"size"`
+	Target string `json:"target,omitempty"`
+}
+
+// DryRun is the machine readable result of a dry-run (--dry-run=json)
+type DryRun struct {
+	Output  string        `json:"output"`
+	Entries []DryRunEntry `json:"entries"`
+}
@@ -12,5 +12,7 @@ type PromptItem struct {
 	Options      []interface{} `mapstructure:"options"`
 	AllowEdit    bool          `mapstructure:"allow_edit"`
 	HideEntered  bool          `mapstructure:"hide_entered"`
+	// Secret answers are never recorded in the generation manifest
+	Secret bool `mapstructure:"secret"`
 	// Validation   []string `mapstructure:"validation"`
 }
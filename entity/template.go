@@ -0,0 +1,108 @@
+package entity
+
+type TemplateConfig struct {
+	Template TemplateMetadata `mapstructure:"template"`
+	Engine   string           `mapstructure:"engine"`
+
+	// RenderMode is "all" (default) or "suffix" to only render the files ending in .j2/.jinja
+	// (it applies to the files of the template declaring it)
+	RenderMode string `mapstructure:"render_mode"`
+
+	// StrictUndefined fails renders using undefined variables (default true)
+	StrictUndefined *bool `mapstructure:"strict_undefined"`
+
+	Manifest ManifestConfig `mapstructure:"manifest"`
+
+	// Format lists the formatters run over generated files (e.g. go, json, yaml)
+	Format []string `mapstructure:"format"`
+
+	// IncludesDir holds the files shared through include/import/extends (default _includes)
+	IncludesDir string `mapstructure:"includes_dir"`
+
+	Extends         string   `mapstructure:"extends"`
+	DeleteBaseFiles []string `mapstructure:"delete_base_files"`
+
+	CopyWithoutRender []string `mapstructure:"copy_without_render"`
+	ForceRender       []string `mapstructure:"force_render"`
+
+	// ConditionalPaths is declared as a map from glob to expression (or a list of path/when)
+	ConditionalPaths []ConditionalPath `mapstructure:"conditional_paths"`
+
+	// SkipIfExists lists globs of generated paths (e.g. "**/.env") never overwritten when they exist
+	SkipIfExists []string `mapstructure:"skip_if_exists"`
+
+	Delimiters         Delimiters          `mapstructure:"delimiters"`
+	DelimiterOverrides []DelimiterOverride `mapstructure:"delimiter_overrides"`
+
+	Text          TextOptions    `mapstructure:",squash"`
+	TextOverrides []TextOverride `mapstructure:"text_overrides"`
+
+	Umask     string     `mapstructure:"umask"`
+	FileModes []FileMode `mapstructure:"file_modes"`
+}
+
+// ConditionalPath only generates the template paths matching Path when the
+// Jinja expression When is truthy
+type ConditionalPath struct {
+	Path string `mapstructure:"path"`
+	When string `mapstructure:"when"`
+}
+
+// FileMode sets the permissions (octal, e.g. "0755") of the generated paths matching Path
+type FileMode struct {
+	Path string `mapstructure:"path"`
+	Mode string `mapstructure:"mode"`
+}
+
+// TemplateLayer is one template of an extends chain (base templates come first)
+type TemplateLayer struct {
+	RootPath   string
+	ConfigPath string
+
+	Prompt Prompt
+	Config TemplateConfig
+}
+
+type TemplateMetadata struct {
+	Name             string   `mapstructure:"name" json:"name"`
+	Description      string   `mapstructure:"description" json:"description"`
+	Version          string   `mapstructure:"version" json:"version"`
+	Author           string   `mapstructure:"author" json:"author"`
+	Tags             []string `mapstructure:"tags" json:"tags"`
+	RequiresScaffold string   `mapstructure:"requires_scaffold" json:"requires_scaffold"`
+}
+
+// Delimiters customizes the Jinja syntax (unset ones keep the Jinja defaults)
+type Delimiters struct {
+	BlockStart    string `mapstructure:"block_start"`
+	BlockEnd      string `mapstructure:"block_end"`
+	VariableStart string `mapstructure:"variable_start"`
+	VariableEnd   string `mapstructure:"variable_end"`
+	CommentStart  string `mapstructure:"comment_start"`
+	CommentEnd    string `mapstructure:"comment_end"`
+}
+
+// TextOptions controls how the text of template files is read and generated
+// (unset ones keep the defaults)
+type TextOptions struct {
+	// Newline is lf, crlf or preserve (default)
+	Newline string `mapstructure:"newline"`
+	// KeepTrailingNewline keeps the final newline of rendered files (default true, copied files always keep it)
+	KeepTrailingNewline *bool `mapstructure:"keep_trailing_newline"`
+	// BOM is keep (default), strip or add
+	BOM string `mapstructure:"bom"`
+	// Encoding of the template files (default utf-8), generated files use the same encoding
+	Encoding string `mapstructure:"encoding"`
+}
+
+// TextOverride uses other text options for the files matching Path
+type TextOverride struct {
+	Path string      `mapstructure:"path"`
+	Text TextOptions `mapstructure:",squash"`
+}
+
+// DelimiterOverride uses other delimiters for the files matching Path
+type DelimiterOverride struct {
+	Path       string     `mapstructure:"path"`
+	Delimiters Delimiters `mapstructure:",squash"`
+}
@@ -2,6 +2,8 @@ package setup
 
 import (
 	"log/slog"
+	"os"
+	"path/filepath"
 
 	viper "github.com/spf13/viper"
 )
@@ -22,3 +24,29 @@ func SetupConfig(logger *slog.Logger) {
 
 	// logger.Info("Configuration loaded successfully...", slog.String("side", "client"))
 }
+
+// SetupUserConfig merges the user configuration (e.g. source abbreviations) when present
+func SetupUserConfig(logger *slog.Logger) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return
+	}
+
+	userConfigPath := filepath.Join(configDir, "scaffold", "config.yaml")
+	if _, err := os.Stat(userConfigPath); err != nil {
+		return
+	}
+
+	userConfig := viper.New()
+	userConfig.SetConfigFile(userConfigPath)
+	err = userConfig.ReadInConfig()
+	if err != nil {
+		logger.Warn("Failed to load user configuration", "config", userConfigPath, "err", err)
+		return
+	}
+
+	err = viper.MergeConfigMap(userConfig.AllSettings())
+	if err != nil {
+		logger.Warn("Failed to merge user configuration", "config", userConfigPath, "err", err)
+	}
+}